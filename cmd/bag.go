@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+const bagCommandLongDesc = `Calculate the sheet size and score lines for a paper gift bag.
+
+The sheet is laid out left to right as front, side, back, side and glue tab,
+with a hem folded in at the top and flaps folded under to form the base. Each
+side is scored down its middle so it folds in.
+
+  pbc bag --width 5 --depth 2.5 --height 7 --units in`
+
+// NewBagCommand returns a new bag command.
+func NewBagCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bag",
+		Short: "calculate the sheet and score lines for a gift bag",
+		Long:  bagCommandLongDesc,
+		Args:  cobra.NoArgs,
+		RunE:  RunBagCmd,
+	}
+
+	cmd.Flags().Float64P("width", "w", 0, "width of the bag front")
+	cmd.Flags().Float64P("depth", "d", 0, "depth of the bag sides")
+	cmd.Flags().Float64("height", 0, "height of the bag")
+	addUnitFlags(cmd)
+	addBoardFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewBagCommand())
+}
+
+// RunBagCmd is the entrypoint for the bag command.
+func RunBagCmd(cmd *cobra.Command, args []string) error {
+	width, err := cmd.Flags().GetFloat64("width")
+	if err != nil {
+		return err
+	}
+
+	depth, err := cmd.Flags().GetFloat64("depth")
+	if err != nil {
+		return err
+	}
+
+	height, err := cmd.Flags().GetFloat64("height")
+	if err != nil {
+		return err
+	}
+
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	bag, err := calculate.GiftBagSpec{
+		Width:  width,
+		Depth:  depth,
+		Height: height,
+		Board:  settings.Board,
+		Unit:   settings.Unit,
+	}.Calculate()
+	if err != nil {
+		return err
+	}
+
+	recordCalculation(cmd, settings, map[string]float64{"width": width, "depth": depth, "height": height}, bag)
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), bag)
+	}
+
+	p := settings.Precision
+
+	cmd.Printf("Sheet: %0.*f x %0.*f\n", p, bag.Sheet.Length, p, bag.Sheet.Width)
+	cmd.Printf("Glue tab: %0.*f\n", p, bag.GlueTab)
+	cmd.Printf("Top hem: %0.*f\n", p, bag.Hem)
+	cmd.Printf("Base flaps: %0.*f\n", p, bag.BaseFlap)
+	printScoreLines(cmd, bag.ScoreLines, p)
+
+	return nil
+}
+
+// printScoreLines prints the positions of vertical and horizontal score lines, if any.
+func printScoreLines(cmd *cobra.Command, lines []calculate.ScoreLine, precision int) {
+	for _, dir := range []struct{ direction, from string }{
+		{calculate.ScoreVertical, "left"},
+		{calculate.ScoreHorizontal, "top"},
+	} {
+		var positions []string
+
+		for _, l := range lines {
+			if l.Direction == dir.direction {
+				positions = append(positions, strconv.FormatFloat(l.Position, 'f', precision, 64))
+			}
+		}
+
+		if len(positions) > 0 {
+			cmd.Printf("Score lines from %s: %s\n", dir.from, strings.Join(positions, ", "))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestNewBagCommand(t *testing.T) {
+	got := NewBagCommand()
+
+	assert.Equal(t, "bag", got.Name())
+	assert.True(t, got.Runnable())
+}
+
+func TestRunBagCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "imperial",
+			flags: map[string]string{"width": "5", "depth": "2.5", "height": "7", "units": "in", "precision": "3"},
+			want: "Sheet: 15.500 x 9.875\n" +
+				"Glue tab: 0.500\n" +
+				"Top hem: 1.000\n" +
+				"Base flaps: 1.875\n" +
+				"Score lines from left: 5.000, 6.250, 7.500, 12.500, 13.750, 15.000\n" +
+				"Score lines from top: 1.000, 8.000\n",
+		},
+		{
+			name:    "missing height",
+			flags:   map[string]string{"width": "5", "depth": "2.5"},
+			wantErr: calculate.ErrInvalidDimension,
+		},
+		{
+			name:    "board limit",
+			flags:   map[string]string{"width": "12", "depth": "4", "height": "20", "mini": "true"},
+			wantErr: calculate.ErrBoardLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewBagCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunBagCmd(cmd, nil)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunBagCmd_JSON(t *testing.T) {
+	defer func(f string) { outputFormat = f }(outputFormat)
+	outputFormat = outputJSON
+
+	cmd := NewBagCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("width", "12"))
+	require.NoError(t, cmd.Flags().Set("depth", "4"))
+	require.NoError(t, cmd.Flags().Set("height", "20"))
+
+	require.NoError(t, RunBagCmd(cmd, nil))
+
+	var got calculate.GiftBag
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, calculate.Size{Length: 33.5, Width: 25.5}, got.Sheet)
+	assert.Len(t, got.ScoreLines, 8)
+}
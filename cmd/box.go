@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+const boxCommandLongDesc = `Calculate the paper size and punch points for a box and its lid.
+
+The box is punched like an envelope, then punched again further along each
+edge so the sides fold up. With --lid a matching lid is calculated, larger than
+the box by the clearance on every side. Lengths given to --lid-height and
+--clearance may have their own unit.
+
+  pbc box -l 4 -w 3 --height 1 --units in --lid --lid-height 1.5 --clearance 0.125in`
+
+// NewBoxCommand returns a new box command.
+func NewBoxCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "box",
+		Short: "calculate punch positions for a box and lid",
+		Long:  boxCommandLongDesc,
+		Args:  cobra.NoArgs,
+		RunE:  RunBoxCmd,
+	}
+
+	cmd.Flags().Float64P("length", "l", 0, "inside length of the box")
+	cmd.Flags().Float64P("width", "w", 0, "inside width of the box")
+	cmd.Flags().Float64("height", 0, "height of the box sides (default the stack thickness with --item)")
+	cmd.Flags().StringArray("item", nil, "a piece of content, length x width with an optional x thickness; repeat for a stack")
+	cmd.Flags().Bool("lid", false, "calculate a matching lid")
+	cmd.Flags().String("lid-height", "", "height of the lid sides (default the box height)")
+	cmd.Flags().String("clearance", "", "room between the box and each lid side, which may be 0 (default 0.125in or 0.3cm)")
+	cmd.Flags().Bool("steps", false, "show step-by-step instructions for the punch board and a scoring board")
+	addUnitFlags(cmd)
+	addBoardFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewBoxCommand())
+}
+
+// RunBoxCmd is the entrypoint for the box command.
+func RunBoxCmd(cmd *cobra.Command, args []string) error {
+	length, err := cmd.Flags().GetFloat64("length")
+	if err != nil {
+		return err
+	}
+
+	width, err := cmd.Flags().GetFloat64("width")
+	if err != nil {
+		return err
+	}
+
+	height, err := cmd.Flags().GetFloat64("height")
+	if err != nil {
+		return err
+	}
+
+	lid, err := cmd.Flags().GetBool("lid")
+	if err != nil {
+		return err
+	}
+
+	steps, err := cmd.Flags().GetBool("steps")
+	if err != nil {
+		return err
+	}
+
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	lidHeight, err := getLengthFlag(cmd, "lid-height", settings.Unit)
+	if err != nil {
+		return err
+	}
+
+	// zero is a valid clearance, so the default applies only when the flag is not given
+	var clearance *float64
+
+	if cmd.Flags().Changed("clearance") {
+		c, err := getLengthFlag(cmd, "clearance", settings.Unit)
+		if err != nil {
+			return err
+		}
+
+		clearance = &c
+	}
+
+	items, err := getItems(cmd, settings.Unit)
+	if err != nil {
+		return err
+	}
+
+	box, err := calculate.BoxSpec{
+		Length:    length,
+		Width:     width,
+		Height:    height,
+		Board:     settings.Board,
+		Unit:      settings.Unit,
+		Lid:       lid,
+		LidHeight: lidHeight,
+		Clearance: clearance,
+		Steps:     steps,
+		Items:     items,
+	}.Calculate()
+	if err != nil {
+		return err
+	}
+
+	recordCalculation(cmd, settings, map[string]float64{
+		"length": box.Spec.Length, "width": box.Spec.Width, "height": box.Spec.Height,
+	}, box)
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), box)
+	}
+
+	printStack(cmd, box.Stack)
+	printBoxPart(cmd, "Box", box.Base, settings.Precision)
+
+	if box.Lid != nil {
+		cmd.Println()
+		printBoxPart(cmd, "Lid", *box.Lid, settings.Precision)
+		cmd.Printf("Lid clearance: %0.*f\n", settings.Precision, box.Clearance)
+	}
+
+	return nil
+}
+
+// printBoxPart writes the size, paper size, punch points and any board
+// instructions of a box part as text.
+func printBoxPart(cmd *cobra.Command, name string, p calculate.BoxPart, precision int) {
+	cmd.Printf("%s (length x width x height): %0.2f x %0.2f x %0.2f\n", name, p.Length, p.Width, p.Height)
+	cmd.Printf("%s paper size: %0.*f\n", name, precision, p.PaperSize)
+	cmd.Printf("%s punch locations: %0.*f, %0.*f\n", name, precision, p.PunchLocation, precision, p.SecondPunch)
+
+	if in := p.Instructions; in != nil {
+		printSteps(cmd, name+" punch board", in.PunchBoard, precision, 0)
+		printSteps(cmd, name+" scoring board", in.ScoringBoard, precision, 0)
+	}
+}
+
+// getLengthFlag returns a length flag such as "0.125in" in the lengths of unit,
+// or zero if the flag is empty. A length without a unit is taken to be in unit.
+func getLengthFlag(cmd *cobra.Command, name string, unit calculate.Unit) (float64, error) {
+	s, err := cmd.Flags().GetString(name)
+	if err != nil || s == "" {
+		return 0, err
+	}
+
+	m, err := calculate.ParseMeasurement(s, unit.Length())
+	if err != nil {
+		return 0, usage(err)
+	}
+
+	return m.To(unit.Length()).Float64(), nil
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestNewBoxCommand(t *testing.T) {
+	got := NewBoxCommand()
+
+	assert.Equal(t, "box", got.Name())
+	assert.True(t, got.Runnable())
+}
+
+func TestRunBoxCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "box only",
+			flags: map[string]string{"length": "4", "width": "3", "height": "1", "units": "in", "precision": "3"},
+			want: "Box (length x width x height): 4.00 x 3.00 x 1.00\n" +
+				"Box paper size: 7.239\n" +
+				"Box punch locations: 2.559, 3.973\n",
+		},
+		{
+			name:  "steps",
+			flags: map[string]string{"length": "4", "width": "3", "height": "1", "units": "in", "precision": "3", "steps": "true"},
+			want: "Box (length x width x height): 4.00 x 3.00 x 1.00\n" +
+				"Box paper size: 7.239\n" +
+				"Box punch locations: 2.559, 3.973\n" +
+				"\nBox punch board:\n" +
+				"  1. Place the paper with its top edge against the guide and its left edge at 2.559 on the ruler\n" +
+				"  2. Punch\n" +
+				"  3. Score along the groove from the punch to the edge of the paper\n" +
+				"  4. Slide the paper along the guide until its left edge is at 3.973 on the ruler, the second punch point\n" +
+				"  5. Punch\n" +
+				"  6. Score along the groove from the punch to the edge of the paper\n" +
+				boxSideSteps(7) + boxSideSteps(14) + boxSideSteps(21) +
+				"\nBox scoring board:\n" +
+				"  1. Turn the paper so a corner touches the top fence with its diagonal square to the fence\n" +
+				"  2. Score at 2.619 from the fence\n" +
+				"  3. Score at 3.619 from the fence\n" +
+				"  4. Rotate the paper 90° counterclockwise\n" +
+				"  5. Score at 2.119 from the fence\n" +
+				"  6. Score at 3.119 from the fence\n" +
+				"  7. Rotate the paper 90° counterclockwise\n" +
+				"  8. Score at 2.619 from the fence\n" +
+				"  9. Score at 3.619 from the fence\n" +
+				" 10. Rotate the paper 90° counterclockwise\n" +
+				" 11. Score at 2.119 from the fence\n" +
+				" 12. Score at 3.119 from the fence\n",
+		},
+		{
+			name:  "items",
+			flags: map[string]string{"item": "4x3x0.5", "units": "in", "precision": "3"},
+			want: "Stack (length x width x thickness): 4.00 x 3.00 x 0.50, 1 item\n" +
+				"Box (length x width x height): 4.00 x 3.00 x 0.50\n" +
+				"Box paper size: 6.532\n" +
+				"Box punch locations: 2.559, 3.266\n",
+		},
+		{
+			name: "lid",
+			flags: map[string]string{
+				"length": "4", "width": "3", "height": "1", "units": "in", "precision": "3",
+				"lid": "true", "lid-height": "1.5", "clearance": "0.125in",
+			},
+			want: "Box (length x width x height): 4.00 x 3.00 x 1.00\n" +
+				"Box paper size: 7.239\n" +
+				"Box punch locations: 2.559, 3.973\n" +
+				"\n" +
+				"Lid (length x width x height): 4.25 x 3.25 x 1.50\n" +
+				"Lid paper size: 8.300\n" +
+				"Lid punch locations: 2.736, 4.857\n" +
+				"Lid clearance: 0.125\n",
+		},
+		{
+			name:  "clearance in another unit",
+			flags: map[string]string{"length": "8", "width": "6", "height": "2", "lid": "true", "clearance": "5mm"},
+			want: "Box (length x width x height): 8.00 x 6.00 x 2.00\n" +
+				"Box paper size: 14.9\n" +
+				"Box punch locations: 5.3, 8.2\n" +
+				"\n" +
+				"Lid (length x width x height): 9.00 x 7.00 x 2.00\n" +
+				"Lid paper size: 16.3\n" +
+				"Lid punch locations: 6.0, 8.9\n" +
+				"Lid clearance: 0.5\n",
+		},
+		{
+			name:  "no clearance",
+			flags: map[string]string{"length": "8", "width": "6", "height": "2", "lid": "true", "clearance": "0"},
+			want: "Box (length x width x height): 8.00 x 6.00 x 2.00\n" +
+				"Box paper size: 14.9\n" +
+				"Box punch locations: 5.3, 8.2\n" +
+				"\n" +
+				"Lid (length x width x height): 8.00 x 6.00 x 2.00\n" +
+				"Lid paper size: 14.9\n" +
+				"Lid punch locations: 5.3, 8.2\n" +
+				"Lid clearance: 0.0\n",
+		},
+		{
+			name:    "bad clearance",
+			flags:   map[string]string{"length": "8", "width": "6", "height": "2", "lid": "true", "clearance": "lots"},
+			wantErr: calculate.ErrSyntax,
+		},
+		{
+			name:    "lid too big",
+			flags:   map[string]string{"length": "6", "width": "5", "height": "1", "units": "in", "lid": "true", "lid-height": "2.5"},
+			wantErr: calculate.ErrBoardLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewBoxCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunBoxCmd(cmd, nil)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunBoxCmd_JSON(t *testing.T) {
+	defer func(f string) { outputFormat = f }(outputFormat)
+	outputFormat = outputJSON
+
+	cmd := NewBoxCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("length", "8"))
+	require.NoError(t, cmd.Flags().Set("width", "6"))
+	require.NoError(t, cmd.Flags().Set("height", "2"))
+	require.NoError(t, cmd.Flags().Set("lid", "true"))
+
+	require.NoError(t, RunBoxCmd(cmd, nil))
+
+	var got calculate.Box
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	require.NotNil(t, got.Lid)
+	assert.Equal(t, 0.3, got.Clearance)
+	assert.InDelta(t, 8.6, got.Lid.Length, 1e-9)
+}
+
+// boxSideSteps returns the punch board steps for a box side after the first, starting at step n.
+func boxSideSteps(n int) string {
+	lines := []string{
+		"Rotate the paper 90° counterclockwise",
+		"Line up the score line from the first punch point on the last side with the guide",
+		"Punch",
+		"Score along the groove from the punch to the edge of the paper",
+		"Line up the score line from the second punch point on the last side with the guide",
+		"Punch",
+		"Score along the groove from the punch to the edge of the paper",
+	}
+
+	var s string
+	for i, l := range lines {
+		s += fmt.Sprintf("%3d. %s\n", n+i, l)
+	}
+
+	return s
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+const cardstockCommandLongDesc = `List common paper and cardstock weights with their typical caliper and the
+fold allowance each adds to an envelope.
+
+Any of these names, or a weight or caliper, can be given to envelope --paper.
+
+  pbc cardstock --units in
+  pbc envelope -l 7 -w 5 --units in --paper "110 lb cover"`
+
+// cardstockRow is a Cardstock entry with its fold allowance in the chosen units.
+type cardstockRow struct {
+	calculate.PaperWeight
+	FoldAllowance float64 `json:"fold_allowance"`
+}
+
+// NewCardstockCommand returns a new cardstock command.
+func NewCardstockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cardstock",
+		Short: "list common cardstock weights and their fold allowance",
+		Long:  cardstockCommandLongDesc,
+		Args:  cobra.NoArgs,
+		RunE:  RunCardstockCmd,
+	}
+
+	addUnitFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewCardstockCommand())
+}
+
+// RunCardstockCmd is the entrypoint for the cardstock command.
+func RunCardstockCmd(cmd *cobra.Command, args []string) error {
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]cardstockRow, len(calculate.Cardstock))
+	for i, p := range calculate.Cardstock {
+		rows[i] = cardstockRow{PaperWeight: p, FoldAllowance: calculate.FoldAllowance(p.Caliper, settings.Unit)}
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), rows)
+	}
+
+	p := settings.Precision
+	if p < minAllowancePrecision {
+		p = minAllowancePrecision
+	}
+
+	cmd.Printf("%-14s %5s %8s %10s\n", "Paper", "gsm", "caliper", "allowance")
+
+	for _, r := range rows {
+		cmd.Printf("%-14s %5.0f %5.2f mm %10.*f\n", r.Name, r.GSM, r.Caliper, p, r.FoldAllowance)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestNewCardstockCommand(t *testing.T) {
+	got := NewCardstockCommand()
+
+	assert.Equal(t, "cardstock", got.Name())
+	assert.True(t, got.Runnable())
+}
+
+func TestRunCardstockCmd(t *testing.T) {
+	cmd := NewCardstockCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, cmd.Flags().Set("units", "in"))
+	require.NoError(t, RunCardstockCmd(cmd, nil))
+
+	want := "Paper            gsm  caliper  allowance\n" +
+		"copy paper        80  0.10 mm      0.004\n" +
+		"65 lb cover      176  0.23 mm      0.009\n" +
+		"80 lb cover      216  0.27 mm      0.011\n" +
+		"100 lb cover     270  0.32 mm      0.013\n" +
+		"110 lb cover     298  0.36 mm      0.014\n" +
+		"120 lb cover     325  0.41 mm      0.016\n"
+	assert.Equal(t, want, out.String())
+}
+
+func TestRunCardstockCmd_JSON(t *testing.T) {
+	defer func(f string) { outputFormat = f }(outputFormat)
+	outputFormat = outputJSON
+
+	cmd := NewCardstockCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, RunCardstockCmd(cmd, nil))
+
+	var got []cardstockRow
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	require.Len(t, got, len(calculate.Cardstock))
+	assert.Equal(t, "80 lb cover", got[2].Name)
+	assert.InDelta(t, 0.027, got[2].FoldAllowance, 1e-9)
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/config"
+)
+
+const configCommandLongDesc = `Manage the configuration file.
+
+Settings are read from the config file, overridden by ` + config.EnvPrefix + `_* environment
+variables, which are in turn overridden by command line flags.`
+
+// boundFlags holds the flags bound to each setting, so the source of a value can be reported.
+var boundFlags = map[string]*pflag.Flag{}
+
+// bindFlag binds a flag to a setting.
+func bindFlag(key string, flag *pflag.Flag) {
+	cobra.CheckErr(viper.BindPFlag(key, flag))
+
+	boundFlags[key] = flag
+}
+
+// NewConfigCommand returns a new config command.
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "manage the configuration file",
+		Long:  configCommandLongDesc,
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		NewConfigInitCommand(),
+		NewConfigShowCommand(),
+		NewConfigValidateCommand(),
+		NewConfigPathCommand(),
+	)
+
+	return cmd
+}
+
+// NewConfigInitCommand returns a new config init command.
+func NewConfigInitCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "write a commented default config file",
+		Args:  cobra.NoArgs,
+		RunE:  RunConfigInitCmd,
+	}
+
+	cmd.Flags().Bool("force", false, "overwrite an existing config file")
+
+	return cmd
+}
+
+// NewConfigShowCommand returns a new config show command.
+func NewConfigShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "show the effective configuration and where each value came from",
+		Args:  cobra.NoArgs,
+		RunE:  RunConfigShowCmd,
+	}
+}
+
+// NewConfigValidateCommand returns a new config validate command.
+func NewConfigValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "check the config file for errors",
+		Args:  cobra.NoArgs,
+		RunE:  RunConfigValidateCmd,
+	}
+}
+
+// NewConfigPathCommand returns a new config path command.
+func NewConfigPathCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "path",
+		Short: "print the location of the config file",
+		Args:  cobra.NoArgs,
+		RunE:  RunConfigPathCmd,
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(NewConfigCommand())
+}
+
+// configFilePath returns the config file in use, or the default location when none was found.
+func configFilePath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+
+	if f := viper.ConfigFileUsed(); f != "" {
+		return f, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", failure(err)
+	}
+
+	return filepath.Join(home, ".pbc", "config"), nil
+}
+
+// RunConfigInitCmd is the entrypoint for the config init command.
+func RunConfigInitCmd(cmd *cobra.Command, args []string) error {
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(path); err == nil && !force {
+		return fmt.Errorf("config file %s already exists: use --force to overwrite it", path)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return failure(err)
+	}
+
+	if err = os.WriteFile(path, config.Default(), 0o600); err != nil {
+		return failure(err)
+	}
+
+	cmd.Println("Wrote", path)
+
+	return nil
+}
+
+// setting is an effective configuration value and where it came from.
+type setting struct {
+	Key    string `json:"key"`
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+}
+
+// valueSource returns where the effective value of a setting came from.
+func valueSource(key string) string {
+	if f, ok := boundFlags[key]; ok && f.Changed {
+		return "flag --" + f.Name
+	}
+
+	if _, ok := os.LookupEnv(config.EnvVar(key)); ok {
+		return "env " + config.EnvVar(key)
+	}
+
+	if viper.InConfig(key) {
+		return "file " + viper.ConfigFileUsed()
+	}
+
+	return "default"
+}
+
+// RunConfigShowCmd is the entrypoint for the config show command.
+func RunConfigShowCmd(cmd *cobra.Command, args []string) error {
+	settings := make([]setting, 0, len(config.Keys))
+
+	for _, k := range config.Keys {
+		settings = append(settings, setting{
+			Key:    k.Name,
+			Value:  viper.Get(k.Name),
+			Source: valueSource(k.Name),
+		})
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), settings)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+
+	for _, s := range settings {
+		fmt.Fprintf(w, "%s\t%v\t%s\n", s.Key, s.Value, s.Source)
+	}
+
+	return w.Flush()
+}
+
+// RunConfigValidateCmd is the entrypoint for the config validate command.
+func RunConfigValidateCmd(cmd *cobra.Command, args []string) error {
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return failure(err)
+	}
+
+	problems := config.Validate(data)
+
+	if outputFormat == outputJSON {
+		if err = writeJSON(cmd.OutOrStdout(), struct {
+			File   string                    `json:"file"`
+			Valid  bool                      `json:"valid"`
+			Errors []*config.ValidationError `json:"errors"`
+		}{path, len(problems) == 0, append([]*config.ValidationError{}, problems...)}); err != nil {
+			return failure(err)
+		}
+	} else {
+		for _, p := range problems {
+			cmd.Printf("%s:%s\n", path, p)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d problem(s) in %s", config.ErrInvalid, len(problems), path)
+	}
+
+	if outputFormat != outputJSON {
+		cmd.Printf("%s: OK\n", path)
+	}
+
+	return nil
+}
+
+// RunConfigPathCmd is the entrypoint for the config path command.
+func RunConfigPathCmd(cmd *cobra.Command, args []string) error {
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	cmd.Println(path)
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		cmd.PrintErrln("config file does not exist: run 'pbc config init' to create it")
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/config"
+)
+
+func TestNewConfigCommand(t *testing.T) {
+	got := NewConfigCommand()
+
+	assert.Equal(t, "config", got.Name())
+	assert.False(t, got.Runnable())
+
+	for _, name := range []string{"init", "show", "validate", "path"} {
+		sub, _, err := got.Find([]string{name})
+		require.NoError(t, err)
+		assert.Equal(t, name, sub.Name())
+		assert.True(t, sub.Runnable())
+	}
+}
+
+// withConfigFile points the commands at a config file in a temporary directory.
+func withConfigFile(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "pbc", "config")
+
+	cfgFile = path
+	t.Cleanup(func() { cfgFile = "" })
+
+	return path
+}
+
+func TestRunConfigInitCmd(t *testing.T) {
+	path := withConfigFile(t)
+
+	cmd := NewConfigInitCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, RunConfigInitCmd(cmd, nil))
+	assert.Equal(t, "Wrote "+path+"\n", out.String())
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, config.Default(), got)
+
+	assert.Error(t, RunConfigInitCmd(cmd, nil))
+
+	require.NoError(t, cmd.Flags().Set("force", "true"))
+	assert.NoError(t, RunConfigInitCmd(cmd, nil))
+}
+
+func TestRunConfigValidateCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: "logging:\n  level: info\n",
+			want: ": OK\n",
+		},
+		{
+			name:    "invalid",
+			data:    "logging:\n  lvl: info\n",
+			want:    ":2:3: logging.lvl: unknown setting\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := withConfigFile(t)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+			require.NoError(t, os.WriteFile(path, []byte(tt.data), 0o600))
+
+			cmd := NewConfigValidateCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			err := RunConfigValidateCmd(cmd, nil)
+
+			assert.Equal(t, path+tt.want, out.String())
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, config.ErrInvalid)
+				assert.Equal(t, ExitInvalidConfig, toExitError(err).Code)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValueSource(t *testing.T) {
+	assert.Equal(t, "default", valueSource("logging.level"))
+
+	t.Setenv(config.EnvVar("logging.level"), "debug")
+	assert.Equal(t, "env PBCALC_LOGGING_LEVEL", valueSource("logging.level"))
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+const convertCommandLongDesc = `Convert a length between millimetres, centimetres and inches.
+
+The length may be a decimal, a fraction or a mixed number, with or without a
+unit. Lengths without a unit are in the default units. Conversion is exact;
+only the result is rounded for display.
+
+  pbc convert 5 1/2in --to mm
+  5 1/2 in = 139.7 mm`
+
+// convertResult is the machine-readable result of a conversion.
+type convertResult struct {
+	From      calculate.Measurement `json:"from"`
+	To        calculate.Measurement `json:"to"`
+	Formatted string                `json:"formatted"`
+}
+
+// NewConvertCommand returns a new convert command.
+func NewConvertCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert <length> --to <unit>",
+		Short: "convert a length between units",
+		Long:  convertCommandLongDesc,
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  RunConvertCmd,
+	}
+
+	cmd.Flags().String("to", "", "unit to convert to (mm, cm or in)")
+	cmd.Flags().Int64("fraction", 0, "show the result rounded to the nearest 1/N")
+	addUnitFlags(cmd)
+
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewConvertCommand())
+}
+
+// RunConvertCmd is the entrypoint for the convert command.
+func RunConvertCmd(cmd *cobra.Command, args []string) error {
+	to, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return err
+	}
+
+	target, err := calculate.ParseLengthUnit(to)
+	if err != nil {
+		return usage(err)
+	}
+
+	fraction, err := cmd.Flags().GetInt64("fraction")
+	if err != nil {
+		return err
+	}
+
+	if fraction < 0 {
+		return usage(fmt.Errorf("fraction denominator must be positive, got %d", fraction))
+	}
+
+	// a length given without a unit is in the units setting
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	from, err := calculate.ParseMeasurement(strings.Join(args, " "), settings.Unit.Length())
+	if err != nil {
+		return usage(err)
+	}
+
+	result := from.To(target)
+	formatted := result.Format(settings.Precision, fraction)
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), convertResult{From: from, To: result, Formatted: formatted})
+	}
+
+	cmd.Printf("%s = %s\n", from, formatted)
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestNewConvertCommand(t *testing.T) {
+	got := NewConvertCommand()
+
+	assert.Equal(t, "convert", got.Name())
+	assert.True(t, got.Runnable())
+}
+
+func TestRunConvertCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		flags   map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "mixed inches to mm",
+			args:  []string{"5", "1/2in"},
+			flags: map[string]string{"to": "mm"},
+			want:  "5 1/2 in = 139.7 mm\n",
+		},
+		{
+			name:  "cm to fractional inches",
+			args:  []string{"14.8cm"},
+			flags: map[string]string{"to": "in", "fraction": "16"},
+			want:  "14.8 cm = 5 13/16 in\n",
+		},
+		{
+			name:  "exact inches",
+			args:  []string{"139.7", "mm"},
+			flags: map[string]string{"to": "in"},
+			want:  "139.7 mm = 5 1/2 in\n",
+		},
+		{
+			name:  "default units",
+			args:  []string{"5½"},
+			flags: map[string]string{"to": "cm", "units": "in", "precision": "2"},
+			want:  "5 1/2 in = 13.97 cm\n",
+		},
+		{
+			name:  "too large for int64",
+			args:  []string{"100000000000000000in"},
+			flags: map[string]string{"to": "mm"},
+			want:  "100000000000000000 in = 2540000000000000000.0 mm\n",
+		},
+		{
+			name:  "eighteen decimal places",
+			args:  []string{"0.123456789012345678in"},
+			flags: map[string]string{"to": "mm", "precision": "6"},
+			want:  "61728394506172839/500000000000000000 in = 3.135802 mm\n",
+		},
+		{
+			name:    "unknown target",
+			args:    []string{"5in"},
+			flags:   map[string]string{"to": "furlong"},
+			wantErr: errors.New(`unknown unit "furlong"`),
+		},
+		{
+			name:    "not a number",
+			args:    []string{"five", "in"},
+			flags:   map[string]string{"to": "mm"},
+			wantErr: calculate.ErrSyntax,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewConvertCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunConvertCmd(cmd, tt.args)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunConvertCmd_JSON(t *testing.T) {
+	defer func(f string) { outputFormat = f }(outputFormat)
+	outputFormat = outputJSON
+
+	cmd := NewConvertCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("to", "cm"))
+
+	require.NoError(t, RunConvertCmd(cmd, []string{`3/4"`}))
+
+	var got convertResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, calculate.Inch, got.From.Unit)
+	assert.Equal(t, "1 181/200", got.To.Value.Mixed())
+	assert.Equal(t, "1.9 cm", got.Formatted)
+}
@@ -1,26 +1,56 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 
 	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/config"
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/diagram"
 )
 
 const envelopeCommandLongDesc = "LONG DESCRIPTION GOES HERE."
 
+// minAllowancePrecision is the fewest decimal places a fold allowance is shown with.
+const minAllowancePrecision = 3
+
 // NewEnvelopeCommand returns a new envelope command.
 func NewEnvelopeCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "envelope",
 		Short: "calculate punch positions for an envelope",
 		Long:  envelopeCommandLongDesc,
-		Run:   RunEnvelopeCmd,
+		Args:  cobra.NoArgs,
+		RunE:  RunEnvelopeCmd,
 	}
 
 	cmd.Flags().Float64P("length", "l", 0, "length of envelope")
 	cmd.Flags().Float64P("width", "w", 0, "width of envelope")
-	cmd.Flags().Bool("loose", false, "loose envelope")
-	cmd.Flags().Bool("mini", false, "mini punch board")
+	cmd.Flags().String("orientation", "auto", "content edge to measure the punch along: width, length or auto for the shorter")
+	cmd.Flags().StringArray("item", nil, "a piece of content, length x width with an optional x thickness; repeat for a stack")
+	cmd.Flags().Bool("stdin", false, "read one measurement set per line from stdin")
+	cmd.Flags().Bool("exact", false, "calculate without floating point error, rounding only the results")
+	cmd.Flags().Int64("fraction", 0, "show results as fractions rounded to the nearest 1/N (implies --exact)")
+	cmd.Flags().Float64("liner-inset", 0, "add a liner this far in from the paper edges")
+	cmd.Flags().Bool("inserts", false, "size an insert card, layer mat and belly band for the content")
+	cmd.Flags().String("paper", "", "paper weight or caliper for fold allowance, such as 216gsm, 110lb or 0.3mm")
+	cmd.Flags().String("stock-sizes", config.DefaultStockSizes, "round the paper up to the smallest of these square sizes, or standard or none")
+	cmd.Flags().Bool("check-flaps", false, "show how far the flaps reach and where they overlap")
+	cmd.Flags().Bool("steps", false, "show step-by-step instructions for the punch board and a scoring board")
+	cmd.Flags().String("tolerance", "", "report how the results move if every measurement is out by up to this, such as 1mm")
+	cmd.Flags().String("content-tolerance", "", "tolerance on the content length and width, instead of --tolerance")
+	cmd.Flags().String("cut-tolerance", "", "tolerance on the paper size as cut, instead of --tolerance")
+	cmd.Flags().String("punch-tolerance", "", "tolerance on the punch placement, instead of --tolerance")
+	cmd.Flags().Bool("rectangular", false, "use a rectangular sheet instead of a square one")
+	cmd.Flags().Bool("diagram", false, "print a layout diagram")
+	cmd.Flags().String("svg", "", "write the layout to an SVG file")
+	addSpecFlags(cmd)
 
 	return cmd
 }
@@ -30,21 +60,554 @@ func init() {
 }
 
 // RunEnvelopeCmd is the entrypoint for the envelope command.
-func RunEnvelopeCmd(cmd *cobra.Command, args []string) {
-	length, _ := cmd.Flags().GetFloat64("length")
-	width, _ := cmd.Flags().GetFloat64("width")
+func RunEnvelopeCmd(cmd *cobra.Command, args []string) error {
+	length, err := cmd.Flags().GetFloat64("length")
+	if err != nil {
+		return err
+	}
+
+	width, err := cmd.Flags().GetFloat64("width")
+	if err != nil {
+		return err
+	}
+
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	useStdin, err := cmd.Flags().GetBool("stdin")
+	if err != nil {
+		return err
+	}
+
+	if useStdin {
+		if err := checkStdinFlags(cmd); err != nil {
+			return err
+		}
+
+		return runSession(cmd, newSession(settings), "")
+	}
+
+	exact, err := cmd.Flags().GetBool("exact")
+	if err != nil {
+		return err
+	}
+
+	fraction, err := cmd.Flags().GetInt64("fraction")
+	if err != nil {
+		return err
+	}
+
+	if fraction < 0 {
+		return usage(fmt.Errorf("fraction denominator must be positive, got %d", fraction))
+	}
+
+	linerInset, err := cmd.Flags().GetFloat64("liner-inset")
+	if err != nil {
+		return err
+	}
+
+	inserts, err := cmd.Flags().GetBool("inserts")
+	if err != nil {
+		return err
+	}
+
+	checkFlaps, err := cmd.Flags().GetBool("check-flaps")
+	if err != nil {
+		return err
+	}
+
+	steps, err := cmd.Flags().GetBool("steps")
+	if err != nil {
+		return err
+	}
+
+	var caliper float64
+
+	if paper, err := cmd.Flags().GetString("paper"); err != nil {
+		return err
+	} else if paper != "" {
+		weight, err := calculate.ParsePaperWeight(paper)
+		if err != nil {
+			return usage(err)
+		}
+
+		caliper = weight.Caliper
+	}
+
+	items, err := getItems(cmd, settings.Unit)
+	if err != nil {
+		return err
+	}
+
+	orientation, err := getOrientation(cmd)
+	if err != nil {
+		return err
+	}
+
+	tolerance, err := getTolerance(cmd, settings.Unit)
+	if err != nil {
+		return err
+	}
+
+	spec := calculate.EnvelopeSpec{
+		Length:     length,
+		Width:      width,
+		Loose:      settings.Loose,
+		Board:      settings.Board,
+		Unit:       settings.Unit,
+		LinerInset: linerInset,
+		Inserts:    inserts,
+		CheckFlaps: checkFlaps,
+		Steps:      steps,
+		Caliper:    caliper,
+		Items:      items,
+
+		Orientation: orientation,
+		Tolerance:   tolerance,
+	}
+
+	rectangular, err := cmd.Flags().GetBool("rectangular")
+	if err != nil {
+		return err
+	}
+
+	if rectangular {
+		return printRectangularEnvelope(cmd, spec, settings.Precision, fraction)
+	}
+
+	// stock sizes come from the flag or its setting, like the spec settings
+	if spec.StockSizes, err = calculate.ParseStockSizes(viper.GetString("defaults.stock_sizes"), settings.Unit); err != nil {
+		return settingError(cmd, "defaults.stock_sizes", err)
+	}
+
+	if exact || fraction > 0 {
+		return printExactEnvelope(cmd, spec, settings.Precision, fraction)
+	}
+
+	env, err := spec.Calculate()
+	if err != nil {
+		return err
+	}
+
+	recordEnvelope(cmd, env.Spec, env)
+
+	if err := writeLayout(cmd, env); err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), env)
+	}
+
+	printStack(cmd, env.Stack)
+	cmd.Printf("Content (length x width): %0.2f x %0.2f\n", env.Spec.Length, env.Spec.Width)
+	cmd.Printf("Paper size: %0.*f\n", settings.Precision, env.PaperSize)
+	cmd.Printf("Punch location: %0.*f\n", settings.Precision, env.PunchLocation)
+	printOrientation(cmd, env)
+
+	return printLayout(cmd, env, settings.Precision, 0)
+}
+
+// stdinFlags are the envelope flags that apply to every line read with --stdin.
+var stdinFlags = map[string]bool{
+	"stdin": true, "units": true, "board": true, "content": true, "precision": true, "loose": true, "mini": true,
+}
+
+// checkStdinFlags returns an error for any other envelope flag given with
+// --stdin, since each line gives its own dimensions.
+func checkStdinFlags(cmd *cobra.Command) error {
+	var err error
+
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if err == nil && !stdinFlags[f.Name] && cmd.LocalFlags().Lookup(f.Name) != nil {
+			err = usage(fmt.Errorf("--stdin cannot be used with --%s", f.Name))
+		}
+	})
+
+	return err
+}
+
+// printExactEnvelope calculates an envelope exactly and prints it, rounding only for display.
+func printExactEnvelope(cmd *cobra.Command, spec calculate.EnvelopeSpec, precision int, fraction int64) error {
+	env, err := spec.CalculateExact()
+	if err != nil {
+		return err
+	}
+
+	recordEnvelope(cmd, env.Spec, env.Envelope())
+
+	if err := writeLayout(cmd, env.Envelope()); err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), env.Envelope())
+	}
+
+	printStack(cmd, env.Stack)
+	cmd.Printf("Content (length x width): %0.2f x %0.2f\n", env.Spec.Length, env.Spec.Width)
+	cmd.Printf("Paper size: %s\n", env.PaperSize.Format(precision, fraction))
+	cmd.Printf("Punch location: %s\n", env.PunchLocation.Format(precision, fraction))
+	printOrientation(cmd, env.Envelope())
+
+	return printLayout(cmd, env.Envelope(), precision, fraction)
+}
+
+// printRectangularEnvelope calculates an envelope on rectangular paper and prints it.
+// Lengths are shown as in formatLength.
+func printRectangularEnvelope(cmd *cobra.Command, spec calculate.EnvelopeSpec, precision int, fraction int64) error {
+	for _, name := range []string{
+		"liner-inset", "inserts", "check-flaps", "stock-sizes", "steps", "orientation",
+		"tolerance", "content-tolerance", "cut-tolerance", "punch-tolerance", "diagram", "svg", "exact",
+	} {
+		if cmd.Flags().Changed(name) {
+			return usage(fmt.Errorf("--rectangular cannot be used with --%s", name))
+		}
+	}
+
+	env, err := spec.CalculateRectangular()
+	if err != nil {
+		return err
+	}
+
+	// rectangular envelopes are never rounded up to a stock size
+	recordEnvelope(cmd, env.Spec, env, "stock-sizes")
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), env)
+	}
+
+	punches := make([]string, len(env.Punches))
+	for i, p := range env.Punches {
+		punches[i] = formatEdgePunch(p, env.Paper, precision, fraction)
+	}
+
+	printStack(cmd, env.Stack)
+	cmd.Printf("Content (length x width): %0.2f x %0.2f\n", env.Spec.Length, env.Spec.Width)
+	cmd.Printf("Paper size: %s (square layout %s, %.0f%% less paper)\n",
+		formatSize(env.Paper, precision, fraction), formatLength(env.SquareSize, precision, fraction), env.PercentSaved)
+	cmd.Printf("Content angle: %.1f°\n", env.Angle)
+	cmd.Printf("Punch locations: %s\n", strings.Join(punches, "; "))
+	cmd.Printf("Closing flap depth: %s\n", formatLength(env.ClosingFlap, precision, fraction))
+	cmd.Printf("Side flap depth: %s\n", formatLength(env.SideFlap, precision, fraction))
+
+	for _, p := range env.Punches {
+		if !p.Placeable {
+			cmd.Printf("Warning: the board guide cannot reach the %s punch from either end of the edge\n", p.Edge)
+		}
+	}
+
+	return nil
+}
+
+// recordEnvelope notes an envelope calculation for the history. See recordCalculation.
+func recordEnvelope(cmd *cobra.Command, spec calculate.EnvelopeSpec, result any, ignored ...string) {
+	recordCalculation(cmd, specSettings{Board: spec.Board, Unit: spec.Unit},
+		map[string]float64{"length": spec.Length, "width": spec.Width}, result, ignored...)
+}
+
+// getItems returns the content items given with the item flag, measured in the
+// lengths of unit. Items replace the length and width flags.
+func getItems(cmd *cobra.Command, unit calculate.Unit) ([]calculate.ContentItem, error) {
+	values, err := cmd.Flags().GetStringArray("item")
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+
+	for _, name := range []string{"length", "width"} {
+		if cmd.Flags().Changed(name) {
+			return nil, usage(fmt.Errorf("--item cannot be used with --%s", name))
+		}
+	}
+
+	items := make([]calculate.ContentItem, len(values))
+
+	for i, v := range values {
+		if items[i], err = calculate.ParseContentItem(v, unit.Length()); err != nil {
+			return nil, usage(err)
+		}
+	}
 
-	isLoose, _ := cmd.Flags().GetBool("loose")
-	isMini, _ := cmd.Flags().GetBool("mini")
+	return items, nil
+}
 
-	cmd.Printf("Content (length x width): %0.2f x %0.2f\n", length, width)
+// getOrientation returns the orientation given with the orientation flag.
+func getOrientation(cmd *cobra.Command) (calculate.Orientation, error) {
+	s, err := cmd.Flags().GetString("orientation")
+	if err != nil {
+		return calculate.OrientationAuto, err
+	}
 
-	paperSize, punchLocation, err := calculate.CalculateEnvelope(length, width, isLoose, isMini)
+	o, err := calculate.ParseOrientation(s)
 	if err != nil {
-		cmd.PrintErrln(err)
+		return o, usage(err)
+	}
+
+	return o, nil
+}
+
+// getTolerance returns the tolerance given with the tolerance flags, measured
+// in the lengths of unit, or nil if none are set. Each of the content, cut and
+// punch tolerances defaults to the tolerance flag.
+func getTolerance(cmd *cobra.Command, unit calculate.Unit) (*calculate.Tolerance, error) {
+	names := []string{"tolerance", "content-tolerance", "cut-tolerance", "punch-tolerance"}
+	values := make([]float64, len(names))
+	set := false
+
+	for i, name := range names {
+		if !cmd.Flags().Changed(name) {
+			continue
+		}
+
+		v, err := getLengthFlag(cmd, name, unit)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i], set = v, true
+	}
+
+	if !set {
+		return nil, nil
+	}
+
+	t := &calculate.Tolerance{Content: values[0], Cut: values[0], Punch: values[0]}
+
+	for i, f := range []*float64{&t.Content, &t.Cut, &t.Punch} {
+		if cmd.Flags().Changed(names[i+1]) {
+			*f = values[i+1]
+		}
+	}
+
+	return t, nil
+}
+
+// printOrientation prints which content edge runs left from the punch location, and so is measured for it.
+func printOrientation(cmd *cobra.Command, env calculate.Envelope) {
+	left, right := "width", "length"
+	if env.Orientation == calculate.OrientationLength {
+		left, right = right, left
+	}
+
+	cmd.Printf("Orientation: %s edge left of the punch, %s edge right\n", left, right)
+}
+
+// printStack prints the size of a stack of content items, if there is one.
+func printStack(cmd *cobra.Command, st *calculate.Stack) {
+	if st == nil {
 		return
 	}
 
-	cmd.Printf("Paper size: %0.1f\n", paperSize)
-	cmd.Printf("Punch location: %0.1f\n", punchLocation)
+	noun := "items"
+	if st.Count == 1 {
+		noun = "item"
+	}
+
+	cmd.Printf("Stack (length x width x thickness): %0.2f x %0.2f x %0.2f, %d %s\n", st.Length, st.Width, st.Thickness, st.Count, noun)
+}
+
+// formatEdgePunch describes a punch on a sheet edge, such as "top 3.8" or
+// "bottom 9.2 (1.3 from the right)" for one the guide reaches from the far end.
+func formatEdgePunch(p calculate.EdgePunch, paper calculate.Size, precision int, fraction int64) string {
+	s := p.Edge + " " + formatLength(p.Position, precision, fraction)
+	if !p.FromEnd {
+		return s
+	}
+
+	edge, end := paper.Length, "right"
+	if p.Edge == calculate.EdgeLeft || p.Edge == calculate.EdgeRight {
+		edge, end = paper.Width, "bottom"
+	}
+
+	return s + " (" + formatLength(edge-p.Position, precision, fraction) + " from the " + end + ")"
+}
+
+// writeLayout writes the layout to the file named by the svg flag, if any.
+func writeLayout(cmd *cobra.Command, env calculate.Envelope) error {
+	path, err := cmd.Flags().GetString("svg")
+	if err != nil || path == "" {
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(diagram.SVG(env)), 0o600); err != nil {
+		return failure(err)
+	}
+
+	return nil
+}
+
+// printLayout prints the stock size, the fold allowance, the liner, the
+// inserts, the flap analysis, the board instructions and, if the diagram flag
+// is set, a layout diagram. Lengths are shown as in formatLength.
+func printLayout(cmd *cobra.Command, env calculate.Envelope, precision int, fraction int64) error {
+	if s := env.Stock; s != nil {
+		cmd.Printf("Stock size: %s (extra margin %s)\n", formatLength(s.PaperSize, precision, fraction), formatLength(s.ExtraMargin, precision, fraction))
+		cmd.Printf("Stock punch location: %s\n", formatLength(s.PunchLocation, precision, fraction))
+	}
+
+	if env.FoldAllowance > 0 {
+		// allowances are much smaller than the results, so show them in more detail
+		p := precision
+		if p < minAllowancePrecision {
+			p = minAllowancePrecision
+		}
+
+		cmd.Printf("Fold allowance: %s per fold for %.2f mm paper, margin +%s\n",
+			formatLength(env.FoldAllowance, p, fraction), env.Spec.Caliper, formatLength(env.FoldCompensation, p, fraction))
+	}
+
+	if l := env.Liner; l != nil {
+		cmd.Printf("Liner size: %s (inset %s)\n", formatLength(l.Size, precision, fraction), formatLength(l.Inset, precision, fraction))
+		cmd.Printf("Liner fold marks: %s\n", formatFoldMarks(l, precision, fraction))
+		cmd.Printf("Liner flap depth: %s\n", formatLength(l.FlapDepth, precision, fraction))
+	}
+
+	if in := env.Inserts; in != nil {
+		cmd.Printf("Insert card: %s (clearance %s)\n", formatSize(in.Card, precision, fraction), formatLength(in.Clearance, precision, fraction))
+		cmd.Printf("Layer mat: %s\n", formatSize(in.Mat, precision, fraction))
+		cmd.Printf("Belly band: %s x %s (overlap %s)\n", formatLength(in.BellyBand.Length, precision, fraction),
+			formatLength(in.BellyBand.Width, precision, fraction), formatLength(in.BellyBand.Overlap, precision, fraction))
+	}
+
+	if f := env.Flaps; f != nil {
+		printFlaps(cmd, f, precision, fraction)
+	}
+
+	if s := env.Sensitivity; s != nil {
+		printSensitivity(cmd, s, precision, fraction)
+	}
+
+	if in := env.Instructions; in != nil {
+		printSteps(cmd, "Punch board", in.PunchBoard, precision, fraction)
+		printSteps(cmd, "Scoring board", in.ScoringBoard, precision, fraction)
+	}
+
+	showDiagram, err := cmd.Flags().GetBool("diagram")
+	if err != nil || !showDiagram {
+		return err
+	}
+
+	cmd.Println()
+	cmd.Print(diagram.ASCII(env, diagram.DefaultWidth))
+
+	return nil
+}
+
+// printSteps prints a board sequence as a numbered list under a heading.
+// Lengths are shown as in formatLength.
+func printSteps(cmd *cobra.Command, board string, steps []calculate.Step, precision int, fraction int64) {
+	length := func(f float64) string { return formatLength(f, precision, fraction) }
+
+	cmd.Printf("\n%s:\n", board)
+
+	for _, s := range steps {
+		cmd.Printf("%3d. %s\n", s.Number, s.Describe(length))
+	}
+}
+
+// printFlaps prints a flap analysis. Lengths are shown as in formatLength.
+func printFlaps(cmd *cobra.Command, f *calculate.FlapAnalysis, precision int, fraction int64) {
+	cmd.Printf("Flap reach: top %s, bottom %s, side %s\n", formatLength(f.TopReach, precision, fraction),
+		formatLength(f.BottomReach, precision, fraction), formatLength(f.SideReach, precision, fraction))
+	cmd.Printf("Side flaps: %s\n", formatOverlap(f.SideOverlap, precision, fraction))
+	cmd.Printf("Top and bottom flaps: %s\n", formatOverlap(f.TopBottomOverlap, precision, fraction))
+	cmd.Printf("Side and bottom flaps: %s\n", formatOverlap(f.SideBottomOverlap, precision, fraction))
+	cmd.Printf("Top and side flaps: %s\n", formatOverlap(f.TopSideOverlap, precision, fraction))
+
+	if f.Covered {
+		cmd.Println("Flaps: all meet")
+	}
+
+	for _, p := range f.Problems {
+		cmd.Printf("Warning: %s\n", p)
+	}
+}
+
+// printSensitivity prints a sensitivity report. Lengths are shown as in formatLength.
+func printSensitivity(cmd *cobra.Command, s *calculate.Sensitivity, precision int, fraction int64) {
+	length := func(f float64) string { return formatLength(f, precision, fraction) }
+	overlap := func(f float64) string { return formatOverlap(f, precision, fraction) }
+
+	// tolerances are much smaller than the results, so show them in more detail
+	p := precision
+	if p < minAllowancePrecision {
+		p = minAllowancePrecision
+	}
+
+	t := s.Tolerance
+	cmd.Printf("Tolerance: content ±%s, cut ±%s, punch ±%s\n",
+		formatLength(t.Content, p, fraction), formatLength(t.Cut, p, fraction), formatLength(t.Punch, p, fraction))
+	cmd.Printf("Paper size range: %s\n", formatRange(s.PaperSize, length))
+	cmd.Printf("Punch location range: %s\n", formatRange(s.PunchLocation, length))
+	cmd.Printf("Margin range: %s\n", formatRange(s.Margin, length))
+	cmd.Printf("Side flaps range: %s\n", formatRange(s.SideOverlap, overlap))
+	cmd.Printf("Top and bottom flaps range: %s\n", formatRange(s.TopBottomOverlap, overlap))
+	cmd.Printf("Side and bottom flaps range: %s\n", formatRange(s.SideBottomOverlap, overlap))
+	cmd.Printf("Top and side flaps range: %s\n", formatRange(s.TopSideOverlap, overlap))
+
+	if s.Fits {
+		cmd.Println("Tolerance: content fits")
+	}
+
+	for _, w := range s.Warnings {
+		cmd.Printf("Warning: %s\n", w)
+	}
+}
+
+// formatRange describes a range, such as "1.2 to 3.4", with each end formatted by format.
+func formatRange(r calculate.Range, format func(float64) string) string {
+	return format(r.Min) + " to " + format(r.Max)
+}
+
+// formatOverlap describes an overlap, such as "overlap 1.2" or "gap 0.3" when it is negative.
+func formatOverlap(f float64, precision int, fraction int64) string {
+	if f < 0 {
+		return "gap " + formatLength(-f, precision, fraction)
+	}
+
+	return "overlap " + formatLength(f, precision, fraction)
+}
+
+// formatFoldMarks lists the fold marks on each edge of a liner, such as "top 1.2, 3.4; right 5.6".
+func formatFoldMarks(l *calculate.Liner, precision int, fraction int64) string {
+	var edges []string
+
+	for _, edge := range []string{calculate.EdgeTop, calculate.EdgeRight, calculate.EdgeBottom, calculate.EdgeLeft} {
+		marks := l.Marks(edge)
+		if len(marks) == 0 {
+			continue
+		}
+
+		positions := make([]string, len(marks))
+		for i, m := range marks {
+			positions[i] = formatLength(m, precision, fraction)
+		}
+
+		edges = append(edges, edge+" "+strings.Join(positions, ", "))
+	}
+
+	return strings.Join(edges, "; ")
+}
+
+// formatSize formats a size as "length x width", with lengths shown as in formatLength.
+func formatSize(s calculate.Size, precision int, fraction int64) string {
+	return formatLength(s.Length, precision, fraction) + " x " + formatLength(s.Width, precision, fraction)
+}
+
+// fractionDigits is the number of decimal places a length is cut to before it
+// is rounded to a fraction, so floating point noise cannot tip a half either way.
+const fractionDigits = 9
+
+// formatLength rounds a length to the nearest 1/fraction when fraction is
+// positive, otherwise to precision decimal places.
+func formatLength(f float64, precision int, fraction int64) string {
+	if fraction > 0 {
+		if r, err := calculate.ParseBigDecimal(strconv.FormatFloat(f, 'f', fractionDigits, 64)); err == nil {
+			return calculate.Exact{A: r}.Format(precision, fraction)
+		}
+	}
+
+	return strconv.FormatFloat(f, 'f', precision, 64)
 }
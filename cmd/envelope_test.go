@@ -1,9 +1,19 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
 )
 
 func TestNewEnvelopeCommand(t *testing.T) {
@@ -12,3 +22,411 @@ func TestNewEnvelopeCommand(t *testing.T) {
 	assert.Equal(t, "envelope", got.Name())
 	assert.True(t, got.Runnable())
 }
+
+func TestRunEnvelopeCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		format  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "text",
+			flags:  map[string]string{"length": "10", "width": "8", "loose": "true"},
+			format: outputText,
+			want:   "Content (length x width): 10.00 x 8.00\nPaper size: 15.7\nPunch location: 7.2\nOrientation: width edge left of the punch, length edge right\n",
+		},
+		{
+			name:   "exact",
+			flags:  map[string]string{"length": "10", "width": "8", "loose": "true", "exact": "true", "precision": "3"},
+			format: outputText,
+			want:   "Content (length x width): 10.00 x 8.00\nPaper size: 15.728\nPunch location: 7.157\nOrientation: width edge left of the punch, length edge right\n",
+		},
+		{
+			name:   "exact long decimals",
+			flags:  map[string]string{"length": "5.123456789", "width": "7.987654321", "units": "in", "exact": "true", "precision": "6"},
+			format: outputText,
+			want:   "Content (length x width): 5.12 x 7.99\nPaper size: 10.145956\nPunch location: 4.060331\nOrientation: length edge left of the punch, width edge right\n",
+		},
+		{
+			name:   "fraction",
+			flags:  map[string]string{"length": "5", "width": "7", "units": "in", "fraction": "16"},
+			format: outputText,
+			want:   "Content (length x width): 5.00 x 7.00\nPaper size: 9 3/8\nPunch location: 4\nOrientation: length edge left of the punch, width edge right\n",
+		},
+		{
+			name:   "liner",
+			flags:  map[string]string{"length": "5", "width": "3", "units": "in", "liner-inset": "0.25", "fraction": "8"},
+			format: outputText,
+			want: "Content (length x width): 5.00 x 3.00\nPaper size: 6 1/2\nPunch location: 2 1/2\nOrientation: width edge left of the punch, length edge right\n" +
+				"Liner size: 6 (inset 1/4)\n" +
+				"Liner fold marks: top 2 1/8, 2 1/2; right 3 1/2, 3 7/8; bottom 3 1/2, 3 7/8; left 2 1/8, 2 1/2\n" +
+				"Liner flap depth: 2 3/4\n",
+		},
+		{
+			name:   "inserts",
+			flags:  map[string]string{"length": "7", "width": "5", "units": "in", "inserts": "true", "fraction": "16"},
+			format: outputText,
+			want: "Content (length x width): 7.00 x 5.00\nPaper size: 9 3/8\nPunch location: 4\nOrientation: width edge left of the punch, length edge right\n" +
+				"Insert card: 6 7/8 x 4 7/8 (clearance 1/16)\n" +
+				"Layer mat: 6 5/8 x 4 5/8\n" +
+				"Belly band: 10 3/4 x 1 (overlap 1)\n",
+		},
+		{
+			name:    "inserts too small",
+			flags:   map[string]string{"length": "10", "width": "0.9", "inserts": "true"},
+			format:  outputText,
+			wantErr: calculate.ErrInvalidDimension,
+		},
+		{
+			name:   "rectangular",
+			flags:  map[string]string{"length": "9.25", "width": "4.25", "units": "in", "mini": "true", "rectangular": "true", "fraction": "16"},
+			format: outputText,
+			want: "Content (length x width): 9.25 x 4.25\n" +
+				"Paper size: 10 1/2 x 6 3/4 (square layout 10 1/16, 30% less paper)\n" +
+				"Content angle: 13.3°\n" +
+				"Punch locations: top 1 1/4; right 2 3/8; bottom 9 1/4 (1 1/4 from the right); left 4 3/8\n" +
+				"Closing flap depth: 2 3/8\n" +
+				"Side flap depth: 1 1/4\n",
+		},
+		{
+			name:    "rectangular with liner",
+			flags:   map[string]string{"length": "22", "width": "11", "rectangular": "true", "liner-inset": "1"},
+			format:  outputText,
+			wantErr: errors.New("--rectangular cannot be used with --liner-inset"),
+		},
+		{
+			name:   "check flaps",
+			flags:  map[string]string{"length": "7", "width": "5", "units": "in", "check-flaps": "true", "fraction": "16"},
+			format: outputText,
+			want: "Content (length x width): 7.00 x 5.00\nPaper size: 9 3/8\nPunch location: 4\nOrientation: width edge left of the punch, length edge right\n" +
+				"Flap reach: top 4 1/8, bottom 4 1/8, side 3 1/8\n" +
+				"Side flaps: gap 3/4\n" +
+				"Top and bottom flaps: overlap 3 1/4\n" +
+				"Side and bottom flaps: overlap 7/8\n" +
+				"Top and side flaps: overlap 7/8\n" +
+				"Flaps: all meet\n",
+		},
+		{
+			name:   "paper weight",
+			flags:  map[string]string{"length": "7", "width": "5", "units": "in", "paper": "110lb", "precision": "3"},
+			format: outputText,
+			want: "Content (length x width): 7.00 x 5.00\nPaper size: 9.400\nPunch location: 3.993\nOrientation: width edge left of the punch, length edge right\n" +
+				"Fold allowance: 0.014 per fold for 0.36 mm paper, margin +0.020\n",
+		},
+		{
+			name:   "stock sizes",
+			flags:  map[string]string{"length": "5", "width": "7", "units": "in", "fraction": "16", "stock-sizes": "6, 8.5, 12"},
+			format: outputText,
+			want: "Content (length x width): 5.00 x 7.00\nPaper size: 9 3/8\nPunch location: 4\nOrientation: length edge left of the punch, width edge right\n" +
+				"Stock size: 12 (extra margin 1 5/16)\nStock punch location: 5 5/16\n",
+		},
+		{
+			name:   "standard stock sizes",
+			flags:  map[string]string{"length": "10", "width": "8", "loose": "true", "stock-sizes": "standard"},
+			format: outputText,
+			want: "Content (length x width): 10.00 x 8.00\nPaper size: 15.7\nPunch location: 7.2\nOrientation: width edge left of the punch, length edge right\n" +
+				"Stock size: 20.0 (extra margin 2.1)\nStock punch location: 9.3\n",
+		},
+		{
+			name:    "no stock size large enough",
+			flags:   map[string]string{"length": "5", "width": "7", "units": "in", "stock-sizes": "6, 8"},
+			wantErr: calculate.ErrInvalidDimension,
+		},
+		{
+			name:    "rectangular with stock sizes",
+			flags:   map[string]string{"length": "9", "width": "4", "rectangular": "true", "stock-sizes": "standard"},
+			wantErr: errors.New("--rectangular cannot be used with --stock-sizes"),
+		},
+		{
+			name:   "items",
+			flags:  map[string]string{"item": "7x5x0.1", "units": "in", "precision": "3"},
+			format: outputText,
+			want: "Stack (length x width x thickness): 7.00 x 5.00 x 0.10, 1 item\n" +
+				"Content (length x width): 7.10 x 5.10\nPaper size: 9.502\nPunch location: 4.044\nOrientation: width edge left of the punch, length edge right\n",
+		},
+		{
+			name:    "items with length",
+			flags:   map[string]string{"item": "7x5", "length": "7"},
+			wantErr: errors.New("--item cannot be used with --length"),
+		},
+		{
+			name:    "invalid item",
+			flags:   map[string]string{"item": "7by5"},
+			wantErr: calculate.ErrSyntax,
+		},
+		{
+			name:   "forced orientation",
+			flags:  map[string]string{"length": "7", "width": "5", "units": "in", "fraction": "16", "orientation": "length"},
+			format: outputText,
+			want: "Content (length x width): 7.00 x 5.00\nPaper size: 9 3/8\nPunch location: 5 3/8\n" +
+				"Orientation: length edge left of the punch, width edge right\n",
+		},
+		{
+			name:    "unknown orientation",
+			flags:   map[string]string{"length": "7", "width": "5", "orientation": "diagonal"},
+			wantErr: errors.New(`unknown orientation "diagonal"`),
+		},
+		{
+			name:    "rectangular with orientation",
+			flags:   map[string]string{"length": "9", "width": "4", "rectangular": "true", "orientation": "width"},
+			wantErr: errors.New("--rectangular cannot be used with --orientation"),
+		},
+		{
+			name:    "rectangular with exact",
+			flags:   map[string]string{"length": "9", "width": "4", "rectangular": "true", "exact": "true"},
+			wantErr: errors.New("--rectangular cannot be used with --exact"),
+		},
+		{
+			name:    "unknown paper weight",
+			flags:   map[string]string{"length": "7", "width": "5", "paper": "80oz"},
+			wantErr: calculate.ErrSyntax,
+		},
+		{
+			name:    "liner too large",
+			flags:   map[string]string{"length": "10", "width": "8", "liner-inset": "8"},
+			format:  outputText,
+			wantErr: calculate.ErrInvalidDimension,
+		},
+		{
+			name:    "negative fraction",
+			flags:   map[string]string{"length": "5", "width": "7", "fraction": "-2"},
+			format:  outputText,
+			wantErr: errors.New("fraction denominator must be positive, got -2"),
+		},
+		{
+			name:    "invalid dimension",
+			flags:   map[string]string{"width": "8"},
+			format:  outputText,
+			wantErr: calculate.ErrInvalidDimension,
+		},
+		{
+			name:    "board limit",
+			flags:   map[string]string{"length": "25", "width": "20"},
+			format:  outputJSON,
+			wantErr: calculate.ErrBoardLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(f string) { outputFormat = f }(outputFormat)
+			outputFormat = tt.format
+
+			cmd := NewEnvelopeCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunEnvelopeCmd(cmd, nil)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunEnvelopeCmd_JSON(t *testing.T) {
+	defer func(f string) { outputFormat = f }(outputFormat)
+	outputFormat = outputJSON
+
+	cmd := NewEnvelopeCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("length", "10"))
+	require.NoError(t, cmd.Flags().Set("width", "8"))
+	require.NoError(t, cmd.Flags().Set("mini", "true"))
+
+	require.NoError(t, RunEnvelopeCmd(cmd, nil))
+
+	var got calculate.Envelope
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, calculate.BoardMini, got.Spec.Board)
+	assert.InDelta(t, 14.07, got.PaperSize, 0.01)
+}
+
+func TestRunEnvelopeCmd_Layout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "envelope.svg")
+
+	cmd := NewEnvelopeCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("length", "10"))
+	require.NoError(t, cmd.Flags().Set("width", "8"))
+	require.NoError(t, cmd.Flags().Set("liner-inset", "1"))
+	require.NoError(t, cmd.Flags().Set("diagram", "true"))
+	require.NoError(t, cmd.Flags().Set("svg", path))
+
+	require.NoError(t, RunEnvelopeCmd(cmd, nil))
+
+	assert.Contains(t, out.String(), "Liner size: 12.9 (inset 1.0)\n")
+	assert.Contains(t, out.String(), "\n+"+strings.Repeat("-", 13))
+	assert.Contains(t, out.String(), ":")
+
+	svg, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(svg), `fill="#f4cccc"`)
+}
+
+func TestRunEnvelopeCmd_Steps(t *testing.T) {
+	cmd := NewEnvelopeCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("length", "7"))
+	require.NoError(t, cmd.Flags().Set("width", "5"))
+	require.NoError(t, cmd.Flags().Set("units", "in"))
+	require.NoError(t, cmd.Flags().Set("fraction", "16"))
+	require.NoError(t, cmd.Flags().Set("steps", "true"))
+
+	require.NoError(t, RunEnvelopeCmd(cmd, nil))
+
+	assert.Contains(t, out.String(), "\nPunch board:\n"+
+		"  1. Place the paper with its top edge against the guide and its left edge at 4 on the ruler\n"+
+		"  2. Punch\n"+
+		"  3. Score along the groove from the punch to the edge of the paper\n"+
+		"  4. Rotate the paper 90° counterclockwise\n"+
+		"  5. Line up the score line you just made with the guide\n")
+	assert.Contains(t, out.String(), " 15. Score along the groove from the punch to the edge of the paper\n")
+	assert.Contains(t, out.String(), "\nScoring board:\n"+
+		"  1. Turn the paper so a corner touches the top fence with its diagonal square to the fence\n"+
+		"  2. Score at 4 1/8 from the fence\n"+
+		"  3. Rotate the paper 90° counterclockwise\n"+
+		"  4. Score at 3 1/8 from the fence\n")
+}
+
+func TestRunEnvelopeCmd_StepsJSON(t *testing.T) {
+	defer func(f string) { outputFormat = f }(outputFormat)
+	outputFormat = outputJSON
+
+	cmd := NewEnvelopeCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("length", "7"))
+	require.NoError(t, cmd.Flags().Set("width", "5"))
+	require.NoError(t, cmd.Flags().Set("steps", "true"))
+
+	require.NoError(t, RunEnvelopeCmd(cmd, nil))
+
+	var got calculate.Envelope
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	require.NotNil(t, got.Instructions)
+	require.Len(t, got.Instructions.PunchBoard, 15)
+	assert.Equal(t, calculate.ActionPlace, got.Instructions.PunchBoard[0].Action)
+	assert.InDelta(t, got.PunchLocation, got.Instructions.PunchBoard[0].Position, 1e-9)
+	assert.Len(t, got.Instructions.ScoringBoard, 8)
+}
+
+func TestRunEnvelopeCmd_Items(t *testing.T) {
+	cmd := NewEnvelopeCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("item", "7x5x0.1"))
+	require.NoError(t, cmd.Flags().Set("item", "5x3.5x0.2"))
+	require.NoError(t, cmd.Flags().Set("units", "in"))
+	require.NoError(t, cmd.Flags().Set("fraction", "16"))
+
+	require.NoError(t, RunEnvelopeCmd(cmd, nil))
+
+	assert.Equal(t, "Stack (length x width x thickness): 7.00 x 5.00 x 0.30, 2 items\n"+
+		"Content (length x width): 7.30 x 5.30\nPaper size: 9 13/16\nPunch location: 4 3/16\nOrientation: width edge left of the punch, length edge right\n", out.String())
+}
+
+func TestRunEnvelopeCmd_Tolerance(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "fits",
+			flags: map[string]string{"tolerance": "1mm", "punch-tolerance": "0.5mm"},
+			want: "Tolerance: content ±0.039, cut ±0.039, punch ±0.020\n" +
+				"Paper size range: 9.3 to 9.5\nPunch location range: 3.9 to 4.0\nMargin range: 0.4 to 0.5\n" +
+				"Side flaps range: gap 0.9 to gap 0.6\nTop and bottom flaps range: overlap 3.1 to overlap 3.4\n" +
+				"Side and bottom flaps range: overlap 0.8 to overlap 1.0\nTop and side flaps range: overlap 0.8 to overlap 1.0\n" +
+				"Tolerance: content fits\n",
+		},
+		{
+			name:  "could not fit",
+			flags: map[string]string{"content-tolerance": "0.5", "punch-tolerance": "0.25", "fraction": "16"},
+			want: "Tolerance: content ±1/2, cut ±0, punch ±1/4\n" +
+				"Paper size range: 8 5/8 to 10 1/16\nPunch location range: 3 3/8 to 4 9/16\nMargin range: -3/16 to 1 1/16\n" +
+				"Side flaps range: gap 2 1/8 to overlap 9/16\nTop and bottom flaps range: overlap 1 7/8 to overlap 4 9/16\n" +
+				"Side and bottom flaps range: gap 1/16 to overlap 1 13/16\nTop and side flaps range: gap 1/16 to overlap 1 13/16\n" +
+				"Warning: content corners could reach 0.166 past the paper edge\n" +
+				"Warning: at worst, side and bottom flaps leave a gap of 0.0821\n" +
+				"Warning: at worst, top and side flaps leave a gap of 0.0821 under the top flap\n",
+		},
+		{
+			name:    "not a length",
+			flags:   map[string]string{"cut-tolerance": "a bit"},
+			wantErr: calculate.ErrSyntax,
+		},
+		{
+			name:    "negative",
+			flags:   map[string]string{"tolerance": "-1mm"},
+			wantErr: calculate.ErrInvalidDimension,
+		},
+		{
+			name:    "rectangular",
+			flags:   map[string]string{"tolerance": "1mm", "rectangular": "true"},
+			wantErr: errors.New("--rectangular cannot be used with --tolerance"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewEnvelopeCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			require.NoError(t, cmd.Flags().Set("length", "7"))
+			require.NoError(t, cmd.Flags().Set("width", "5"))
+			require.NoError(t, cmd.Flags().Set("units", "in"))
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunEnvelopeCmd(cmd, nil)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, strings.HasSuffix(out.String(), "edge right\n"+tt.want), out.String())
+		})
+	}
+}
+
+func TestRunEnvelopeCmd_ToleranceJSON(t *testing.T) {
+	defer func(f string) { outputFormat = f }(outputFormat)
+	outputFormat = outputJSON
+
+	cmd := NewEnvelopeCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("length", "18"))
+	require.NoError(t, cmd.Flags().Set("width", "13"))
+	require.NoError(t, cmd.Flags().Set("tolerance", "1mm"))
+
+	require.NoError(t, RunEnvelopeCmd(cmd, nil))
+
+	var got calculate.Envelope
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	require.NotNil(t, got.Sensitivity)
+	assert.Equal(t, calculate.Tolerance{Content: 0.1, Cut: 0.1, Punch: 0.1}, got.Sensitivity.Tolerance)
+	assert.InDelta(t, got.PaperSize+0.1*(1+math.Sqrt2), got.Sensitivity.PaperSize.Max, 1e-9)
+	assert.True(t, got.Sensitivity.Fits)
+}
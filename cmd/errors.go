@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/config"
+)
+
+// Exit codes returned by the application.
+const (
+	ExitOK               = 0 // success
+	ExitFailure          = 1 // unexpected failure
+	ExitUsage            = 2 // invalid command, argument or flag
+	ExitInvalidDimension = 3 // dimension that cannot be used for a calculation
+	ExitBoardLimit       = 4 // result larger than the board can handle
+	ExitInvalidConfig    = 5 // config file that does not match the supported settings
+)
+
+// Error kinds reported in machine-readable error output.
+const (
+	KindFailure          = "failure"
+	KindUsage            = "usage"
+	KindInvalidDimension = "invalid_dimension"
+	KindBoardLimit       = "board_limit"
+	KindInvalidConfig    = "invalid_config"
+)
+
+// ExitError is an error with the exit code and kind reported for it.
+type ExitError struct {
+	Code int    `json:"code"`
+	Kind string `json:"kind"`
+	Err  error  `json:"-"`
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *ExitError) MarshalJSON() ([]byte, error) {
+	type alias ExitError
+
+	return json.Marshal(struct {
+		*alias
+		Message string `json:"message"`
+	}{(*alias)(e), e.Error()})
+}
+
+// failure marks an error that is not caused by the user's input.
+func failure(err error) error {
+	return &ExitError{Code: ExitFailure, Kind: KindFailure, Err: err}
+}
+
+// usage marks an error caused by an invalid command, argument or flag. A
+// dimension that cannot be used for a calculation keeps its own kind.
+func usage(err error) error {
+	if errors.Is(err, calculate.ErrInvalidDimension) {
+		return err
+	}
+
+	return &ExitError{Code: ExitUsage, Kind: KindUsage, Err: err}
+}
+
+// markUsageErrors makes the command line parser report its errors as usage
+// errors for a command and all of its subcommands.
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return usage(err)
+	})
+
+	args := cmd.Args
+	if args == nil {
+		args = cobra.ArbitraryArgs
+	}
+
+	// cobra checks required flags and flag groups after the arguments, so they
+	// are checked here first to mark their errors
+	cmd.Args = func(c *cobra.Command, a []string) error {
+		for _, validate := range []func() error{
+			func() error { return args(c, a) },
+			c.ValidateRequiredFlags,
+			c.ValidateFlagGroups,
+		} {
+			if err := validate(); err != nil {
+				return usage(err)
+			}
+		}
+
+		return nil
+	}
+
+	for _, c := range cmd.Commands() {
+		markUsageErrors(c)
+	}
+}
+
+// toExitError classifies an error returned by a command. Errors that are not
+// already classified and do not come from a calculation are unexpected failures.
+func toExitError(err error) *ExitError {
+	var ee *ExitError
+
+	switch {
+	case errors.As(err, &ee):
+		return ee
+	case errors.Is(err, calculate.ErrInvalidDimension):
+		return &ExitError{Code: ExitInvalidDimension, Kind: KindInvalidDimension, Err: err}
+	case errors.Is(err, calculate.ErrBoardLimit):
+		return &ExitError{Code: ExitBoardLimit, Kind: KindBoardLimit, Err: err}
+	case errors.Is(err, config.ErrInvalid):
+		return &ExitError{Code: ExitInvalidConfig, Kind: KindInvalidConfig, Err: err}
+	default:
+		return &ExitError{Code: ExitFailure, Kind: KindFailure, Err: err}
+	}
+}
+
+// printError writes an error to the command's error output in the selected output format.
+func printError(cmd *cobra.Command, ee *ExitError) {
+	if outputFormat == outputJSON {
+		if err := writeJSON(cmd.ErrOrStderr(), struct {
+			Error *ExitError `json:"error"`
+		}{ee}); err == nil {
+			return
+		}
+	}
+
+	cmd.PrintErrln("Error:", ee)
+
+	if ee.Kind == KindUsage {
+		cmd.PrintErrln(fmt.Sprintf("Run '%s --help' for usage.", cmd.CommandPath()))
+	}
+}
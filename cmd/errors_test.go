@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestToExitError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantKind string
+	}{
+		{
+			name:     "invalid dimension",
+			err:      fmt.Errorf("%w: length", calculate.ErrInvalidDimension),
+			wantCode: ExitInvalidDimension,
+			wantKind: KindInvalidDimension,
+		},
+		{
+			name:     "board limit",
+			err:      fmt.Errorf("%w: too big", calculate.ErrBoardLimit),
+			wantCode: ExitBoardLimit,
+			wantKind: KindBoardLimit,
+		},
+		{
+			name:     "failure",
+			err:      failure(errors.New("broken pipe")),
+			wantCode: ExitFailure,
+			wantKind: KindFailure,
+		},
+		{
+			name:     "usage",
+			err:      usage(errors.New("unknown flag: --lenght")),
+			wantCode: ExitUsage,
+			wantKind: KindUsage,
+		},
+		{
+			name:     "usage invalid dimension",
+			err:      usage(fmt.Errorf("%w: paper weight", calculate.ErrInvalidDimension)),
+			wantCode: ExitInvalidDimension,
+			wantKind: KindInvalidDimension,
+		},
+		{
+			name:     "unclassified",
+			err:      errors.New("open history.jsonl: permission denied"),
+			wantCode: ExitFailure,
+			wantKind: KindFailure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toExitError(tt.err)
+
+			assert.Equal(t, tt.wantCode, got.Code)
+			assert.Equal(t, tt.wantKind, got.Kind)
+			assert.ErrorIs(t, got, tt.err)
+		})
+	}
+}
+
+func TestMarkUsageErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		{name: "unknown flag", args: []string{"sub", "--lenght", "5"}, wantCode: ExitUsage},
+		{name: "invalid flag value", args: []string{"sub", "--size", "big"}, wantCode: ExitUsage},
+		{name: "unexpected argument", args: []string{"sub", "extra"}, wantCode: ExitUsage},
+		{name: "missing required flag", args: []string{"sub"}, wantCode: ExitUsage},
+		{name: "command error", args: []string{"sub", "--size", "5"}, wantCode: ExitFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &cobra.Command{Use: "test", Args: cobra.NoArgs, SilenceErrors: true, SilenceUsage: true}
+			sub := &cobra.Command{
+				Use:  "sub",
+				Args: cobra.NoArgs,
+				RunE: func(cmd *cobra.Command, args []string) error { return errors.New("disk full") },
+			}
+			sub.Flags().Int("size", 0, "size")
+			_ = sub.MarkFlagRequired("size")
+			root.AddCommand(sub)
+			root.SetOut(&bytes.Buffer{})
+			root.SetArgs(tt.args)
+
+			markUsageErrors(root)
+
+			assert.Equal(t, tt.wantCode, toExitError(root.Execute()).Code)
+		})
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		err    *ExitError
+		want   string
+	}{
+		{
+			name:   "text",
+			format: outputText,
+			err:    toExitError(fmt.Errorf("%w: too big", calculate.ErrBoardLimit)),
+			want:   "Error: board limit exceeded: too big\n",
+		},
+		{
+			name:   "text usage",
+			format: outputText,
+			err:    toExitError(usage(errors.New("bad flag"))),
+			want:   "Error: bad flag\nRun 'test --help' for usage.\n",
+		},
+		{
+			name:   "json",
+			format: outputJSON,
+			err:    toExitError(fmt.Errorf("%w: too big", calculate.ErrBoardLimit)),
+			want: `{
+  "error": {
+    "code": 4,
+    "kind": "board_limit",
+    "message": "board limit exceeded: too big"
+  }
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(f string) { outputFormat = f }(outputFormat)
+			outputFormat = tt.format
+
+			cmd := &cobra.Command{Use: "test"}
+			buf := &bytes.Buffer{}
+			cmd.SetErr(buf)
+
+			printError(cmd, tt.err)
+
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,497 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/config"
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/history"
+)
+
+const historyCommandLongDesc = `List, show, rerun and export past calculations.
+
+Every calculation is saved to ~/.pbc/` + history.FileName + ` with its inputs, board, units and
+result. The history.retention setting limits how many are kept; 0 turns the history off.
+Calculations read from stdin are not saved.`
+
+// dateLayout is the layout of the dates given to the history filter flags.
+const dateLayout = "2006-01-02"
+
+// historyCommands holds the constructor of each command whose calculations are saved, so they can be run again.
+var historyCommands = map[string]func() *cobra.Command{
+	"bag":       NewBagCommand,
+	"box":       NewBoxCommand,
+	"envelope":  NewEnvelopeCommand,
+	"pillowbox": NewPillowBoxCommand,
+	"plan":      NewPlanCommand,
+	"tag":       NewTagCommand,
+}
+
+// calculation is the calculation made by the command being run. It is saved
+// to the history once the command has finished without error.
+var calculation *history.Entry
+
+// NewHistoryCommand returns a new history command.
+func NewHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "list, show, rerun and export past calculations",
+		Long:  historyCommandLongDesc,
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		NewHistoryListCommand(),
+		NewHistoryShowCommand(),
+		NewHistoryRerunCommand(),
+		NewHistoryExportCommand(),
+	)
+
+	return cmd
+}
+
+// NewHistoryListCommand returns a new history list command.
+func NewHistoryListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list past calculations, oldest first",
+		Args:  cobra.NoArgs,
+		RunE:  RunHistoryListCmd,
+	}
+
+	addHistoryFilterFlags(cmd)
+
+	return cmd
+}
+
+// NewHistoryShowCommand returns a new history show command.
+func NewHistoryShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show ID",
+		Short: "show the inputs and result of a past calculation",
+		Args:  cobra.ExactArgs(1),
+		RunE:  RunHistoryShowCmd,
+	}
+}
+
+// NewHistoryRerunCommand returns a new history rerun command.
+func NewHistoryRerunCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rerun ID",
+		Short: "run a past calculation again with the same inputs and settings",
+		Args:  cobra.ExactArgs(1),
+		RunE:  RunHistoryRerunCmd,
+	}
+}
+
+// NewHistoryExportCommand returns a new history export command.
+func NewHistoryExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "export past calculations as JSON or CSV",
+		Args:  cobra.NoArgs,
+		RunE:  RunHistoryExportCmd,
+	}
+
+	addHistoryFilterFlags(cmd)
+	cmd.Flags().Bool("csv", false, "export as CSV instead of JSON")
+	cmd.Flags().String("file", "", "write to this file instead of standard output")
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewHistoryCommand())
+}
+
+// addHistoryFilterFlags adds the flags that select past calculations.
+func addHistoryFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("command", "", "only calculations made by this command, such as envelope")
+	cmd.Flags().Float64("dimension", 0, "only calculations with an input dimension of this size")
+	cmd.Flags().String("since", "", "only calculations on or after this date, YYYY-MM-DD")
+	cmd.Flags().String("until", "", "only calculations on or before this date, YYYY-MM-DD")
+}
+
+// readHistoryFilter returns the filter given with the history filter flags.
+// Dates are in local time and the until date is included.
+func readHistoryFilter(cmd *cobra.Command) (history.Filter, error) {
+	var (
+		f   history.Filter
+		err error
+	)
+
+	if f.Command, err = cmd.Flags().GetString("command"); err != nil {
+		return f, err
+	}
+
+	if f.Dimension, err = cmd.Flags().GetFloat64("dimension"); err != nil {
+		return f, err
+	}
+
+	for _, d := range []struct {
+		name string
+		t    *time.Time
+		days int
+	}{{"since", &f.Since, 0}, {"until", &f.Until, 1}} {
+		s, err := cmd.Flags().GetString(d.name)
+		if err != nil {
+			return f, err
+		}
+
+		if s == "" {
+			continue
+		}
+
+		t, err := time.ParseInLocation(dateLayout, s, time.Local)
+		if err != nil {
+			return f, usage(fmt.Errorf("invalid --%s date %q: expected YYYY-MM-DD", d.name, s))
+		}
+
+		*d.t = t.AddDate(0, 0, d.days)
+	}
+
+	return f, nil
+}
+
+// historyFilePath returns the location of the history file.
+func historyFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", failure(err)
+	}
+
+	return filepath.Join(home, ".pbc", history.FileName), nil
+}
+
+// readHistory returns the saved calculations that pass a filter.
+func readHistory(f history.Filter) ([]history.Entry, error) {
+	path, err := historyFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := history.Read(path)
+	if err != nil {
+		return nil, failure(err)
+	}
+
+	return append([]history.Entry{}, history.Select(entries, f)...), nil
+}
+
+// findHistory returns the saved calculation with the ID given as an argument.
+func findHistory(arg string) (history.Entry, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return history.Entry{}, usage(fmt.Errorf("invalid calculation ID %q", arg))
+	}
+
+	entries, err := readHistory(history.Filter{})
+	if err != nil {
+		return history.Entry{}, err
+	}
+
+	return history.Find(entries, id)
+}
+
+// recordCalculation notes the calculation made by a command, to be saved to
+// the history when it finishes. The dimensions are its named inputs and the
+// result is saved as it is written by --output json. Settings in ignored are
+// not used by the calculation, so they are not saved with it.
+func recordCalculation(cmd *cobra.Command, settings specSettings, dims map[string]float64, result any, ignored ...string) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		log.WithError(err).Warn("could not record calculation")
+		return
+	}
+
+	calculation = &history.Entry{
+		Time:       time.Now().Truncate(time.Second),
+		Command:    cmd.Name(),
+		Args:       commandArgs(cmd, ignored...),
+		Board:      settings.Board.String(),
+		Unit:       settings.Unit.String(),
+		Dimensions: dims,
+		Result:     b,
+	}
+}
+
+// commandArgs returns the flags given to a command, followed by the settings
+// it has flags for that were not given, so that running it again with them
+// uses the same settings even if the config file changes. Settings in ignored
+// are left out.
+func commandArgs(cmd *cobra.Command, ignored ...string) []string {
+	args := []string{}
+
+	local := cmd.LocalFlags()
+
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if local.Lookup(f.Name) == nil {
+			return
+		}
+
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				args = append(args, "--"+f.Name+"="+v)
+			}
+
+			return
+		}
+
+		args = append(args, "--"+f.Name+"="+f.Value.String())
+	})
+
+	keys := make([]string, 0, len(settingFlags))
+	for key := range settingFlags {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := settingFlags[key]
+		if name == "output" || contains(ignored, name) {
+			continue
+		}
+
+		if f := cmd.Flags().Lookup(name); f != nil && !f.Changed {
+			args = append(args, "--"+name+"="+viper.GetString(key))
+		}
+	}
+
+	return args
+}
+
+// contains reports whether s is in list.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// saveCalculation saves the calculation made by the command that was run, if
+// any, to the history. The calculation has already been shown, so a history
+// that cannot be written is only logged.
+func saveCalculation() {
+	e := calculation
+	calculation = nil
+
+	if e == nil {
+		return
+	}
+
+	retention, err := config.ParseRetention(viper.GetString("history.retention"))
+	if err != nil {
+		log.WithError(err).Warn("invalid history retention, calculation not saved")
+		return
+	}
+
+	if retention == 0 {
+		return
+	}
+
+	path, err := historyFilePath()
+	if err == nil {
+		_, err = history.Append(path, *e, retention)
+	}
+
+	if err != nil {
+		log.WithError(err).Warn("could not save calculation to history")
+	}
+}
+
+// RunHistoryListCmd is the entrypoint for the history list command.
+func RunHistoryListCmd(cmd *cobra.Command, args []string) error {
+	f, err := readHistoryFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	entries, err := readHistory(f)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), entries)
+	}
+
+	if len(entries) == 0 {
+		cmd.Println("No calculations found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tTIME\tCOMMAND\tBOARD\tUNITS\tDIMENSIONS")
+
+	for _, e := range entries {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", e.ID, e.Time.Local().Format("2006-01-02 15:04"),
+			e.Command, e.Board, e.Unit, formatDimensions(e.Dimensions))
+	}
+
+	return w.Flush()
+}
+
+// RunHistoryShowCmd is the entrypoint for the history show command.
+func RunHistoryShowCmd(cmd *cobra.Command, args []string) error {
+	e, err := findHistory(args[0])
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), e)
+	}
+
+	var result bytes.Buffer
+	if err := json.Indent(&result, e.Result, "", "  "); err != nil {
+		return failure(err)
+	}
+
+	cmd.Printf("Calculation %d, %s\n", e.ID, e.Time.Local().Format("2006-01-02 15:04:05"))
+	cmd.Printf("Command: pbc %s %s\n", e.Command, strings.Join(e.Args, " "))
+	cmd.Printf("Board: %s\n", e.Board)
+	cmd.Printf("Units: %s\n", e.Unit)
+	cmd.Printf("Dimensions: %s\n", formatDimensions(e.Dimensions))
+	cmd.Printf("Result:\n%s\n", result.String())
+
+	return nil
+}
+
+// RunHistoryRerunCmd is the entrypoint for the history rerun command. The
+// calculation is made by a new instance of the command that made it, and is
+// saved to the history again.
+func RunHistoryRerunCmd(cmd *cobra.Command, args []string) error {
+	e, err := findHistory(args[0])
+	if err != nil {
+		return err
+	}
+
+	newCmd, ok := historyCommands[e.Command]
+	if !ok {
+		return fmt.Errorf("calculation %d was made by unknown command %q", e.ID, e.Command)
+	}
+
+	c := newCmd()
+	c.SetArgs(append([]string{}, e.Args...))
+	c.SetOut(cmd.OutOrStdout())
+	c.SetErr(cmd.ErrOrStderr())
+	c.SilenceErrors = true
+	c.SilenceUsage = true
+
+	return c.Execute()
+}
+
+// RunHistoryExportCmd is the entrypoint for the history export command.
+func RunHistoryExportCmd(cmd *cobra.Command, args []string) error {
+	f, err := readHistoryFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	asCSV, err := cmd.Flags().GetBool("csv")
+	if err != nil {
+		return err
+	}
+
+	path, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+
+	entries, err := readHistory(f)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	if asCSV {
+		err = writeHistoryCSV(&buf, entries)
+	} else {
+		err = writeJSON(&buf, entries)
+	}
+
+	if err != nil {
+		return failure(err)
+	}
+
+	if path == "" {
+		_, err = cmd.OutOrStdout().Write(buf.Bytes())
+		return err
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return failure(err)
+	}
+
+	if outputFormat != outputJSON {
+		cmd.Printf("Wrote %d calculation(s) to %s\n", len(entries), path)
+	}
+
+	return nil
+}
+
+// writeHistoryCSV writes saved calculations as CSV with a header row. The
+// arguments are joined with spaces and the result is compact JSON.
+func writeHistoryCSV(w io.Writer, entries []history.Entry) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"id", "time", "command", "board", "units", "dimensions", "args", "result"}); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		var result bytes.Buffer
+		if err := json.Compact(&result, e.Result); err != nil {
+			return err
+		}
+
+		if err := cw.Write([]string{
+			strconv.Itoa(e.ID), e.Time.Format(time.RFC3339), e.Command, e.Board, e.Unit,
+			formatDimensions(e.Dimensions), strings.Join(e.Args, " "), result.String(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// formatDimensions lists named dimensions in name order, such as "length 7, width 5".
+func formatDimensions(dims map[string]float64) string {
+	names := make([]string, 0, len(dims))
+	for name := range dims {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + " " + strconv.FormatFloat(dims[name], 'g', -1, 64)
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/config"
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/history"
+)
+
+func TestNewHistoryCommand(t *testing.T) {
+	got := NewHistoryCommand()
+
+	assert.Equal(t, "history", got.Name())
+	assert.False(t, got.Runnable())
+
+	for _, name := range []string{"list", "show", "rerun", "export"} {
+		sub, _, err := got.Find([]string{name})
+		require.NoError(t, err)
+		assert.Equal(t, name, sub.Name())
+		assert.True(t, sub.Runnable())
+	}
+}
+
+// withHistory points the history at a home directory in a temporary
+// directory, with the built-in settings, and returns the history file.
+func withHistory(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	initSettings()
+
+	calculation = nil
+	t.Cleanup(func() { calculation = nil })
+
+	return filepath.Join(home, ".pbc", history.FileName)
+}
+
+// runCalculation runs an envelope calculation with the given flags and saves it to the history.
+func runCalculation(t *testing.T, flags map[string]string) {
+	t.Helper()
+
+	cmd := NewEnvelopeCommand()
+	cmd.SetOut(&bytes.Buffer{})
+
+	for k, v := range flags {
+		require.NoError(t, cmd.Flags().Set(k, v))
+	}
+
+	require.NoError(t, RunEnvelopeCmd(cmd, nil))
+	saveCalculation()
+}
+
+func TestSaveCalculation(t *testing.T) {
+	path := withHistory(t)
+
+	runCalculation(t, map[string]string{"length": "5", "width": "3", "units": "in", "mini": "true"})
+
+	got, err := history.Read(path)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	e := got[0]
+	assert.Equal(t, 1, e.ID)
+	assert.Equal(t, "envelope", e.Command)
+	assert.Equal(t, "mini", e.Board)
+	assert.Equal(t, "in", e.Unit)
+	assert.Equal(t, map[string]float64{"length": 5, "width": 3}, e.Dimensions)
+	assert.Equal(t, []string{
+		"--length=5", "--mini=true", "--units=in", "--width=3",
+		"--board=standard", "--content=snug", "--precision=1", "--stock-sizes=none",
+	}, e.Args)
+	assert.WithinDuration(t, time.Now(), e.Time, time.Minute)
+
+	var env struct {
+		PaperSize float64 `json:"paper_size"`
+	}
+	require.NoError(t, json.Unmarshal(e.Result, &env))
+	assert.InDelta(t, 6.16, env.PaperSize, 0.01)
+
+	assert.Nil(t, calculation)
+}
+
+func TestSaveCalculation_Retention(t *testing.T) {
+	path := withHistory(t)
+
+	viper.Set("history.retention", 2)
+
+	for _, l := range []string{"7", "8", "9"} {
+		runCalculation(t, map[string]string{"length": l, "width": "5"})
+	}
+
+	got, err := history.Read(path)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, 2, got[0].ID)
+
+	viper.Set("history.retention", 0)
+	runCalculation(t, map[string]string{"length": "10", "width": "5"})
+
+	got, err = history.Read(path)
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func TestRunHistoryListCmd(t *testing.T) {
+	withHistory(t)
+
+	runCalculation(t, map[string]string{"length": "7", "width": "5", "units": "in"})
+	runCalculation(t, map[string]string{"length": "18", "width": "13"})
+
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		format  string
+		want    []int
+		wantErr bool
+	}{
+		{name: "all", format: outputJSON, want: []int{1, 2}},
+		{name: "dimension", flags: map[string]string{"dimension": "13"}, format: outputJSON, want: []int{2}},
+		{name: "command", flags: map[string]string{"command": "box"}, format: outputJSON, want: []int{}},
+		{name: "today", flags: map[string]string{"since": time.Now().Format(dateLayout), "until": time.Now().Format(dateLayout)}, format: outputJSON, want: []int{1, 2}},
+		{name: "until yesterday", flags: map[string]string{"until": time.Now().AddDate(0, 0, -1).Format(dateLayout)}, format: outputJSON, want: []int{}},
+		{name: "invalid date", flags: map[string]string{"since": "last week"}, format: outputJSON, wantErr: true},
+		{name: "text", flags: map[string]string{"dimension": "7"}, format: outputText, want: []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(f string) { outputFormat = f }(outputFormat)
+			outputFormat = tt.format
+
+			cmd := NewHistoryListCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunHistoryListCmd(cmd, nil)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tt.format == outputText {
+				assert.Contains(t, out.String(), "ID  TIME")
+				assert.Contains(t, out.String(), "  envelope  standard  in     length 7, width 5\n")
+				assert.NotContains(t, out.String(), "length 18")
+				return
+			}
+
+			var got []history.Entry
+			require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+
+			ids := []int{}
+			for _, e := range got {
+				ids = append(ids, e.ID)
+			}
+
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestRunHistoryListCmd_Empty(t *testing.T) {
+	withHistory(t)
+
+	cmd := NewHistoryListCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, RunHistoryListCmd(cmd, nil))
+	assert.Equal(t, "No calculations found\n", out.String())
+}
+
+func TestRunHistoryShowCmd(t *testing.T) {
+	withHistory(t)
+
+	runCalculation(t, map[string]string{"length": "7", "width": "5", "units": "in"})
+
+	tests := []struct {
+		name    string
+		id      string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "found",
+			id:   "1",
+			want: []string{
+				"Calculation 1, ",
+				"Command: pbc envelope --length=7 --units=in --width=5 --board=standard --content=snug --precision=1 --stock-sizes=none\n",
+				"Board: standard\nUnits: in\nDimensions: length 7, width 5\nResult:\n{\n  \"spec\": {\n",
+			},
+		},
+		{name: "not found", id: "2", wantErr: history.ErrNotFound},
+		{name: "not an ID", id: "one", wantErr: errors.New(`invalid calculation ID "one"`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewHistoryShowCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			err := RunHistoryShowCmd(cmd, []string{tt.id})
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			require.NoError(t, err)
+
+			for _, w := range tt.want {
+				assert.Contains(t, out.String(), w)
+			}
+		})
+	}
+}
+
+func TestRunHistoryRerunCmd(t *testing.T) {
+	path := withHistory(t)
+
+	flags := map[string]string{"length": "7", "width": "5", "units": "in", "fraction": "16"}
+	runCalculation(t, flags)
+
+	// later settings do not change a rerun
+	t.Setenv(config.EnvVar("defaults.units"), "cm")
+	t.Setenv(config.EnvVar("defaults.content"), "loose")
+
+	cmd := NewHistoryRerunCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+
+	require.NoError(t, RunHistoryRerunCmd(cmd, []string{"1"}))
+	assert.Equal(t, "Content (length x width): 7.00 x 5.00\nPaper size: 9 3/8\nPunch location: 4\n"+
+		"Orientation: width edge left of the punch, length edge right\n", out.String())
+
+	saveCalculation()
+
+	got, err := history.Read(path)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, got[0].Dimensions, got[1].Dimensions)
+	assert.JSONEq(t, string(got[0].Result), string(got[1].Result))
+
+	assert.ErrorIs(t, RunHistoryRerunCmd(cmd, []string{"3"}), history.ErrNotFound)
+}
+
+func TestRunHistoryExportCmd(t *testing.T) {
+	withHistory(t)
+
+	runCalculation(t, map[string]string{"length": "7", "width": "5", "units": "in"})
+	runCalculation(t, map[string]string{"length": "18", "width": "13"})
+
+	t.Run("json", func(t *testing.T) {
+		cmd := NewHistoryExportCommand()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		require.NoError(t, cmd.Flags().Set("dimension", "18"))
+
+		require.NoError(t, RunHistoryExportCmd(cmd, nil))
+
+		var got []history.Entry
+		require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		require.Len(t, got, 1)
+		assert.Equal(t, 2, got[0].ID)
+	})
+
+	t.Run("csv file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "history.csv")
+
+		cmd := NewHistoryExportCommand()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		require.NoError(t, cmd.Flags().Set("csv", "true"))
+		require.NoError(t, cmd.Flags().Set("file", path))
+
+		require.NoError(t, RunHistoryExportCmd(cmd, nil))
+		assert.Equal(t, "Wrote 2 calculation(s) to "+path+"\n", out.String())
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+
+		rows, err := csv.NewReader(f).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, []string{"id", "time", "command", "board", "units", "dimensions", "args", "result"}, rows[0])
+		assert.Equal(t, []string{"1", "envelope", "standard", "in", "length 7, width 5"},
+			[]string{rows[1][0], rows[1][2], rows[1][3], rows[1][4], rows[1][5]})
+		assert.Contains(t, rows[2][7], `"paper_size":`)
+	})
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Supported output formats.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// validateOutputFormat checks the value of the output flag.
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputText, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q: must be %q or %q", format, outputText, outputJSON)
+	}
+}
+
+// writeJSON writes v to w as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
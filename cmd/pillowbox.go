@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+const pillowBoxCommandLongDesc = `Calculate the sheet size, score lines and end arcs for a pillow box.
+
+The sheet is laid out left to right as front, back and glue tab. Each end of
+the front and back panels is scored along an arc that bulges out past the end
+of the panel by half the box depth.
+
+  pbc pillowbox --width 4 --length 6 --depth 1.5 --units in`
+
+// NewPillowBoxCommand returns a new pillowbox command.
+func NewPillowBoxCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pillowbox",
+		Short: "calculate the sheet, score lines and end arcs for a pillow box",
+		Long:  pillowBoxCommandLongDesc,
+		Args:  cobra.NoArgs,
+		RunE:  RunPillowBoxCmd,
+	}
+
+	cmd.Flags().Float64P("width", "w", 0, "width of the box lying flat")
+	cmd.Flags().Float64P("length", "l", 0, "length of the box")
+	cmd.Flags().Float64P("depth", "d", 0, "depth of the box at its middle")
+	addUnitFlags(cmd)
+	addBoardFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewPillowBoxCommand())
+}
+
+// RunPillowBoxCmd is the entrypoint for the pillowbox command.
+func RunPillowBoxCmd(cmd *cobra.Command, args []string) error {
+	width, err := cmd.Flags().GetFloat64("width")
+	if err != nil {
+		return err
+	}
+
+	length, err := cmd.Flags().GetFloat64("length")
+	if err != nil {
+		return err
+	}
+
+	depth, err := cmd.Flags().GetFloat64("depth")
+	if err != nil {
+		return err
+	}
+
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	box, err := calculate.PillowBoxSpec{
+		Width:  width,
+		Length: length,
+		Depth:  depth,
+		Board:  settings.Board,
+		Unit:   settings.Unit,
+	}.Calculate()
+	if err != nil {
+		return err
+	}
+
+	recordCalculation(cmd, settings, map[string]float64{"width": width, "length": length, "depth": depth}, box)
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), box)
+	}
+
+	p := settings.Precision
+
+	cmd.Printf("Sheet: %0.*f x %0.*f\n", p, box.Sheet.Length, p, box.Sheet.Width)
+	cmd.Printf("Glue tab: %0.*f\n", p, box.GlueTab)
+	cmd.Printf("End arcs: %0.*f deep, %0.*f radius\n", p, box.ArcDepth, p, box.ArcRadius)
+	printScoreLines(cmd, box.ScoreLines, p)
+
+	return nil
+}
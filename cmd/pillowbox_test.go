@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestNewPillowBoxCommand(t *testing.T) {
+	got := NewPillowBoxCommand()
+
+	assert.Equal(t, "pillowbox", got.Name())
+	assert.True(t, got.Runnable())
+}
+
+func TestRunPillowBoxCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "imperial",
+			flags: map[string]string{"width": "4", "length": "6", "depth": "1.5", "units": "in", "precision": "3"},
+			want: "Sheet: 8.500 x 7.500\n" +
+				"Glue tab: 0.500\n" +
+				"End arcs: 0.750 deep, 3.042 radius\n" +
+				"Score lines from left: 4.000, 8.000\n",
+		},
+		{
+			name:    "deeper than wide",
+			flags:   map[string]string{"width": "4", "length": "6", "depth": "5"},
+			wantErr: calculate.ErrInvalidDimension,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewPillowBoxCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunPillowBoxCmd(cmd, nil)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+const planCommandLongDesc = `Plan how many sheets of stock to buy for a run of envelopes.
+
+The envelope paper square is cut from stock sheets in a grid. The plan shows
+how many blanks fit on each sheet, the cuts that separate them and the offcuts
+left over. Stock is given by name (A4, A3, Letter, Legal, Tabloid) or as width x
+height, such as 12x12in or 30.5x30.5cm.
+
+  pbc plan -l 5 -w 7 --units in --stock 12x12in --qty 40`
+
+// defaultStock is the stock sheet used when none is given.
+const defaultStock = "12x12in"
+
+// planResult is the machine-readable result of a stock plan.
+type planResult struct {
+	Envelope calculate.Envelope  `json:"envelope"`
+	Plan     calculate.SheetPlan `json:"plan"`
+	Quantity int                 `json:"quantity"`
+	Sheets   int                 `json:"sheets"`
+	Spare    int                 `json:"spare"` // blanks left over from the last sheet
+}
+
+// NewPlanCommand returns a new plan command.
+func NewPlanCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "plan paper stock for a run of envelopes",
+		Long:  planCommandLongDesc,
+		Args:  cobra.NoArgs,
+		RunE:  RunPlanCmd,
+	}
+
+	cmd.Flags().Float64P("length", "l", 0, "length of envelope")
+	cmd.Flags().Float64P("width", "w", 0, "width of envelope")
+	cmd.Flags().String("stock", defaultStock, "stock sheet: a name such as A4 or letter, or width x height")
+	cmd.Flags().Int("qty", 1, "number of envelopes to make")
+	addSpecFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewPlanCommand())
+}
+
+// RunPlanCmd is the entrypoint for the plan command.
+func RunPlanCmd(cmd *cobra.Command, args []string) error {
+	length, err := cmd.Flags().GetFloat64("length")
+	if err != nil {
+		return err
+	}
+
+	width, err := cmd.Flags().GetFloat64("width")
+	if err != nil {
+		return err
+	}
+
+	stockFlag, err := cmd.Flags().GetString("stock")
+	if err != nil {
+		return err
+	}
+
+	qty, err := cmd.Flags().GetInt("qty")
+	if err != nil {
+		return err
+	}
+
+	if qty < 1 {
+		return usage(fmt.Errorf("quantity must be at least 1, got %d", qty))
+	}
+
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	unit := settings.Unit.Length()
+
+	stock, err := calculate.ParseStockSheet(stockFlag, unit)
+	if err != nil {
+		return usage(err)
+	}
+
+	env, err := calculate.EnvelopeSpec{
+		Length: length,
+		Width:  width,
+		Loose:  settings.Loose,
+		Board:  settings.Board,
+		Unit:   settings.Unit,
+	}.Calculate()
+	if err != nil {
+		return err
+	}
+
+	plan, err := calculate.PlanSheet(env.PaperSize, unit, stock)
+	if err != nil {
+		return err
+	}
+
+	sheets := plan.SheetsFor(qty)
+	result := planResult{Envelope: env, Plan: plan, Quantity: qty, Sheets: sheets, Spare: sheets*plan.PerSheet - qty}
+
+	recordCalculation(cmd, settings, map[string]float64{"length": length, "width": width}, result)
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), result)
+	}
+
+	printPlan(cmd, result, settings.Precision)
+
+	return nil
+}
+
+// printPlan writes a stock plan as text.
+func printPlan(cmd *cobra.Command, r planResult, precision int) {
+	p := r.Plan
+	unit := p.Unit
+
+	cmd.Printf("Blank: %0.*f %s square\n", precision, p.Blank, unit)
+	cmd.Printf("Stock: %s\n", stockName(p.Stock, precision))
+	cmd.Printf("Blanks per sheet: %d (%d x %d)\n", p.PerSheet, p.Columns, p.Rows)
+	cmd.Printf("Sheets for %d envelopes: %d (%d spare blanks)\n", r.Quantity, r.Sheets, r.Spare)
+
+	cmd.Println()
+	cmd.Println("Cut list (per sheet):")
+
+	if len(p.Cuts) == 0 {
+		cmd.Println("  no cuts needed")
+	}
+
+	for i, c := range p.Cuts {
+		edge := "left"
+		if c.Piece == "strip" {
+			edge = "top"
+		}
+
+		each := ""
+		if c.Count > 1 {
+			each = fmt.Sprintf(" (each of %d strips)", c.Count)
+		}
+
+		cmd.Printf("  %d. Cut the %s %0.*f %s from the %s edge%s.\n", i+1, c.Piece, precision, c.Position, unit, edge, each)
+	}
+
+	cmd.Println()
+	cmd.Println("Offcuts (per sheet):")
+
+	if len(p.Offcuts) == 0 {
+		cmd.Println("  none")
+	}
+
+	for _, o := range p.Offcuts {
+		cmd.Printf("  %0.*f x %0.*f %s\n", precision, o.Width, precision, o.Height, unit)
+	}
+}
+
+// stockName describes a stock sheet, rounding its converted size for display.
+func stockName(s calculate.StockSheet, precision int) string {
+	size := fmt.Sprintf("%0.*f x %0.*f %s", precision, s.Width, precision, s.Height, s.Unit)
+	if s.Name == "" {
+		return size
+	}
+
+	return s.Name + " (" + size + ")"
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestNewPlanCommand(t *testing.T) {
+	got := NewPlanCommand()
+
+	assert.Equal(t, "plan", got.Name())
+	assert.True(t, got.Runnable())
+}
+
+func TestRunPlanCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "12x12 stock",
+			flags: map[string]string{"length": "3", "width": "2", "units": "in", "stock": "12x12in", "qty": "40", "precision": "2"},
+			want: "Blank: 4.41 in square\n" +
+				"Stock: 12.00 x 12.00 in\n" +
+				"Blanks per sheet: 4 (2 x 2)\n" +
+				"Sheets for 40 envelopes: 10 (0 spare blanks)\n" +
+				"\n" +
+				"Cut list (per sheet):\n" +
+				"  1. Cut the sheet 4.41 in from the left edge.\n" +
+				"  2. Cut the sheet 8.82 in from the left edge.\n" +
+				"  3. Cut the strip 4.41 in from the top edge (each of 2 strips).\n" +
+				"  4. Cut the strip 8.82 in from the top edge (each of 2 strips).\n" +
+				"\n" +
+				"Offcuts (per sheet):\n" +
+				"  3.18 x 12.00 in\n" +
+				"  8.82 x 3.18 in\n",
+		},
+		{
+			name:    "does not fit",
+			flags:   map[string]string{"length": "16", "width": "12", "stock": "A4", "loose": "true"},
+			wantErr: calculate.ErrInvalidDimension,
+		},
+		{
+			name:    "bad stock",
+			flags:   map[string]string{"length": "10", "width": "8", "stock": "huge"},
+			wantErr: calculate.ErrSyntax,
+		},
+		{
+			name:    "no quantity",
+			flags:   map[string]string{"length": "10", "width": "8", "qty": "0"},
+			wantErr: errors.New("quantity must be at least 1, got 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewPlanCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunPlanCmd(cmd, nil)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestRunPlanCmd_JSON(t *testing.T) {
+	defer func(f string) { outputFormat = f }(outputFormat)
+	outputFormat = outputJSON
+
+	cmd := NewPlanCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Flags().Set("length", "10"))
+	require.NoError(t, cmd.Flags().Set("width", "8"))
+	require.NoError(t, cmd.Flags().Set("stock", "A3"))
+	require.NoError(t, cmd.Flags().Set("qty", "3"))
+
+	require.NoError(t, RunPlanCmd(cmd, nil))
+
+	var got planResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, 2, got.Plan.PerSheet)
+	assert.Equal(t, 2, got.Sheets)
+	assert.Equal(t, 1, got.Spare)
+	assert.Equal(t, "A3", got.Plan.Stock.Name)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/repl"
+)
+
+const replCommandLongDesc = `Read one measurement set per line and print the result for each.
+
+Each line holds a length and width, optionally separated by "x", and any of the
+keywords thick/loose, snug/flat, mini/standard and in/cm. Board and unit
+settings carry over to the following lines; a line with only keywords changes
+the settings without calculating. Enter "quit" or "exit" to leave.
+
+  pbc> 5.5 x 4.25 thick mini in
+  paper 7.6 in, punch 3.3 in`
+
+// replPrompt is shown before each line in the interactive session.
+const replPrompt = "pbc> "
+
+// NewReplCommand returns a new repl command.
+func NewReplCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repl",
+		Short: "calculate envelopes line by line",
+		Long:  replCommandLongDesc,
+		Args:  cobra.NoArgs,
+		RunE:  RunReplCmd,
+	}
+
+	addSpecFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewReplCommand())
+}
+
+// RunReplCmd is the entrypoint for the repl command.
+func RunReplCmd(cmd *cobra.Command, args []string) error {
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	return runSession(cmd, newSession(settings), replPrompt)
+}
+
+// newSession returns a line session with its starting settings, writing JSON
+// lines when that is the output format.
+func newSession(s specSettings) *repl.Session {
+	return &repl.Session{
+		Board:     s.Board,
+		Unit:      s.Unit,
+		Loose:     s.Loose,
+		Precision: s.Precision,
+		JSON:      outputFormat == outputJSON,
+	}
+}
+
+// runSession reads measurement lines from the command's input until it is exhausted.
+func runSession(cmd *cobra.Command, s *repl.Session, prompt string) error {
+	if err := s.Run(cmd.InOrStdin(), cmd.OutOrStdout(), prompt); err != nil {
+		return failure(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReplCommand(t *testing.T) {
+	got := NewReplCommand()
+
+	assert.Equal(t, "repl", got.Name())
+	assert.True(t, got.Runnable())
+}
+
+func TestRunReplCmd(t *testing.T) {
+	cmd := NewReplCommand()
+	out := &bytes.Buffer{}
+
+	cmd.SetIn(strings.NewReader("5.5 x 4.25 thick\n"))
+	cmd.SetOut(out)
+	assert.NoError(t, cmd.Flags().Set("mini", "true"))
+	assert.NoError(t, cmd.Flags().Set("units", "in"))
+
+	assert.NoError(t, RunReplCmd(cmd, nil))
+
+	assert.Equal(t, replPrompt+"paper 7.6 in, punch 3.3 in\n"+replPrompt+"\n", out.String())
+}
+
+func TestRunEnvelopeCmd_Stdin(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		format  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "text",
+			flags:  map[string]string{"units": "in"},
+			format: outputText,
+			want:   "paper 9.4 in, punch 4.0 in\n",
+		},
+		{
+			name:   "json",
+			flags:  map[string]string{"units": "in"},
+			format: outputJSON,
+		},
+		{
+			name:    "with dimensions",
+			flags:   map[string]string{"length": "7"},
+			format:  outputText,
+			wantErr: "--stdin cannot be used with --length",
+		},
+		{
+			name:    "with steps",
+			flags:   map[string]string{"steps": "true"},
+			format:  outputText,
+			wantErr: "--stdin cannot be used with --steps",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(f string) { outputFormat = f }(outputFormat)
+			outputFormat = tt.format
+
+			cmd := NewEnvelopeCommand()
+			out := &bytes.Buffer{}
+			cmd.SetIn(strings.NewReader("7 x 5\n"))
+			cmd.SetOut(out)
+			require.NoError(t, cmd.Flags().Set("stdin", "true"))
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunEnvelopeCmd(cmd, nil)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Equal(t, ExitUsage, toExitError(err).Code)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tt.format == outputJSON {
+				var got struct {
+					PaperSize float64 `json:"paper_size"`
+				}
+				require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+				assert.InDelta(t, 9.3603, got.PaperSize, 1e-4)
+				assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+				return
+			}
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
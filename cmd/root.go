@@ -2,23 +2,27 @@
 package cmd
 
 import (
-	"fmt"
+	"errors"
 	"os"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/config"
 )
 
 // default const values for application.
 const (
-	DefaultLoggingLevel = "warn"
+	DefaultLoggingLevel = config.DefaultLoggingLevel
 )
 
 const rootCommandLongDesc = "LONG DESCRIPTION GOES HERE."
 
 var (
-	cfgFile string
+	cfgFile      string
+	outputFormat string
 
 	// rootCmd represents the base command when called without any subcommands.
 	rootCmd = &cobra.Command{
@@ -28,6 +32,22 @@ var (
 		Long:              rootCommandLongDesc,
 		Args:              cobra.NoArgs,
 		CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			bindCommandFlags(cmd)
+
+			outputFormat = viper.GetString("output.format")
+
+			if err := validateOutputFormat(outputFormat); err != nil {
+				return settingError(cmd, "output.format", err)
+			}
+
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			saveCalculation()
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 )
 
@@ -35,12 +55,39 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.pbc/config)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "output format (text or json)")
+	rootCmd.PersistentFlags().String("log-level", DefaultLoggingLevel, "logging level")
+
+	bindFlag("logging.level", rootCmd.PersistentFlags().Lookup("log-level"))
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+//
+// Errors are reported in the selected output format and the process exits with
+// the code for the kind of error.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	markUsageErrors(rootCmd)
+
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+
+	ee := toExitError(err)
+	printError(cmd, ee)
+	os.Exit(ee.Code)
+}
+
+// initSettings sets up the environment variables and built-in defaults for every setting.
+func initSettings() {
+	viper.SetEnvPrefix(config.EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv() // read in environment variables that match
+
+	for _, k := range config.Keys {
+		viper.SetDefault(k.Name, k.Default)
+	}
 }
 
 // initConfig sets up Viper and Logging.
@@ -61,15 +108,10 @@ func initConfig() {
 		viper.SetConfigName("config")
 	}
 
-	viper.SetEnvPrefix("PBCALC")
-	viper.AutomaticEnv() // read in environment variables that match
-
-	viper.SetDefault("logging.level", DefaultLoggingLevel)
+	initSettings()
 
 	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
-	}
+	readErr := viper.ReadInConfig()
 
 	loggingLevel, err := log.ParseLevel(viper.GetString("logging.level"))
 	if err != nil {
@@ -78,4 +120,15 @@ func initConfig() {
 
 	log.SetLevel(loggingLevel)
 	log.WithFields(log.Fields{"level": loggingLevel}).Debug("set log level")
+
+	var notFound viper.ConfigFileNotFoundError
+
+	switch {
+	case readErr == nil:
+		log.WithFields(log.Fields{"file": viper.ConfigFileUsed()}).Info("using config file")
+	case errors.As(readErr, &notFound):
+		log.Debug("no config file found")
+	default:
+		log.WithError(readErr).Warn("error reading config file, run 'pbc config validate' for details")
+	}
 }
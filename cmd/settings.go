@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/config"
+)
+
+// settingFlags maps settings to the flag that overrides them on any command that has it.
+var settingFlags = map[string]string{
+	"defaults.units":       "units",
+	"defaults.board":       "board",
+	"defaults.content":     "content",
+	"defaults.precision":   "precision",
+	"defaults.stock_sizes": "stock-sizes",
+	"output.format":        "output",
+}
+
+// bindCommandFlags binds the flags of a command to their settings. Several
+// commands share flag names, so this is done for the command being run.
+func bindCommandFlags(cmd *cobra.Command) {
+	for key, name := range settingFlags {
+		if f := cmd.Flags().Lookup(name); f != nil {
+			bindFlag(key, f)
+		}
+	}
+}
+
+// addSpecFlags adds the flags for every setting used by an envelope calculation.
+func addSpecFlags(cmd *cobra.Command) {
+	addUnitFlags(cmd)
+	addBoardFlags(cmd)
+	cmd.Flags().String("content", config.DefaultContent, "content fit (snug or loose)")
+	cmd.Flags().Bool("loose", false, "loose envelope, same as --content loose")
+}
+
+// addUnitFlags adds the flags for the units and precision, which every calculation uses.
+func addUnitFlags(cmd *cobra.Command) {
+	cmd.Flags().String("units", config.DefaultUnits, "measurement units (cm or in)")
+	cmd.Flags().Int("precision", config.DefaultPrecision, "decimal places shown in results")
+}
+
+// addBoardFlags adds the flags for the punch board, for calculations that check its limits.
+func addBoardFlags(cmd *cobra.Command) {
+	cmd.Flags().String("board", config.DefaultBoard, "punch board (standard or mini)")
+	cmd.Flags().Bool("mini", false, "mini punch board, same as --board mini")
+}
+
+// settingError marks an invalid setting as a usage error when it was given with
+// a flag. A value from the environment or config file is not a usage error.
+func settingError(cmd *cobra.Command, key string, err error) error {
+	if f := cmd.Flags().Lookup(settingFlags[key]); f != nil && f.Changed {
+		return usage(err)
+	}
+
+	return err
+}
+
+// specSettings are the settings used by a calculation.
+type specSettings struct {
+	Board     calculate.Board
+	Unit      calculate.Unit
+	Loose     bool
+	Precision int
+}
+
+// readSpecSettings returns the calculation settings for a command. Each value
+// comes from the first of: flag, PBCALC_* environment variable, config file and
+// built-in default. The board and content fit are only read by commands with
+// flags for them; other commands use a standard board and snug fit.
+func readSpecSettings(cmd *cobra.Command) (specSettings, error) {
+	var (
+		s   specSettings
+		err error
+	)
+
+	bindCommandFlags(cmd)
+
+	if s.Unit, err = calculate.ParseUnit(viper.GetString("defaults.units")); err != nil {
+		return s, settingError(cmd, "defaults.units", err)
+	}
+
+	if s.Precision, err = config.ParsePrecision(viper.GetString("defaults.precision")); err != nil {
+		return s, settingError(cmd, "defaults.precision", err)
+	}
+
+	if cmd.Flags().Lookup("board") != nil {
+		if s.Board, err = calculate.ParseBoard(viper.GetString("defaults.board")); err != nil {
+			return s, settingError(cmd, "defaults.board", err)
+		}
+
+		isMini, err := cmd.Flags().GetBool("mini")
+		if err != nil {
+			return s, err
+		}
+
+		if isMini {
+			s.Board = calculate.BoardMini
+		}
+	}
+
+	if cmd.Flags().Lookup("content") != nil {
+		if s.Loose, err = calculate.ParseContent(viper.GetString("defaults.content")); err != nil {
+			return s, settingError(cmd, "defaults.content", err)
+		}
+
+		isLoose, err := cmd.Flags().GetBool("loose")
+		if err != nil {
+			return s, err
+		}
+
+		if isLoose {
+			s.Loose = true
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestReadSpecSettings(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		env       map[string]string
+		flags     map[string]string
+		want      specSettings
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "built-in",
+			want:      specSettings{Board: calculate.BoardStandard, Unit: calculate.UnitMetric, Precision: 1},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "config file",
+			file:      "defaults:\n  units: in\n  board: mini\n  content: loose\n  precision: 3\n",
+			want:      specSettings{Board: calculate.BoardMini, Unit: calculate.UnitImperial, Loose: true, Precision: 3},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "env over config file",
+			file:      "defaults:\n  units: in\n  precision: 3\n",
+			env:       map[string]string{"PBCALC_DEFAULTS_UNITS": "cm"},
+			want:      specSettings{Board: calculate.BoardStandard, Unit: calculate.UnitMetric, Precision: 3},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "flag over env",
+			env:       map[string]string{"PBCALC_DEFAULTS_BOARD": "mini", "PBCALC_DEFAULTS_PRECISION": "2"},
+			flags:     map[string]string{"board": "standard"},
+			want:      specSettings{Board: calculate.BoardStandard, Unit: calculate.UnitMetric, Precision: 2},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "shorthand flags",
+			file:      "defaults:\n  board: standard\n  content: snug\n",
+			flags:     map[string]string{"mini": "true", "loose": "true"},
+			want:      specSettings{Board: calculate.BoardMini, Unit: calculate.UnitMetric, Loose: true, Precision: 1},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "invalid env",
+			env:       map[string]string{"PBCALC_DEFAULTS_UNITS": "furlong"},
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			initSettings()
+
+			viper.SetConfigType("yaml")
+			require.NoError(t, viper.ReadConfig(strings.NewReader(tt.file)))
+
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cmd := NewEnvelopeCommand()
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			got, err := readSpecSettings(cmd)
+
+			tt.assertion(t, err)
+
+			if err == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSettingFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  *cobra.Command
+		want []string
+	}{
+		{name: "envelope", cmd: NewEnvelopeCommand(), want: []string{"units", "precision", "board", "mini", "content", "loose"}},
+		{name: "box", cmd: NewBoxCommand(), want: []string{"units", "precision", "board", "mini"}},
+		{name: "bag", cmd: NewBagCommand(), want: []string{"units", "precision", "board", "mini"}},
+		{name: "pillowbox", cmd: NewPillowBoxCommand(), want: []string{"units", "precision", "board", "mini"}},
+		{name: "tag", cmd: NewTagCommand(), want: []string{"units", "precision"}},
+		{name: "cardstock", cmd: NewCardstockCommand(), want: []string{"units", "precision"}},
+		{name: "convert", cmd: NewConvertCommand(), want: []string{"units", "precision"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"units", "precision", "board", "mini", "content", "loose"} {
+				assert.Equal(t, contains(tt.want, name), tt.cmd.Flags().Lookup(name) != nil, "--%s", name)
+			}
+		})
+	}
+}
+
+func TestReadSpecSettings_UnusedSettings(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	initSettings()
+
+	// a tag has no board or content fit, so their settings are not read
+	t.Setenv("PBCALC_DEFAULTS_BOARD", "huge")
+	t.Setenv("PBCALC_DEFAULTS_CONTENT", "loose")
+	t.Setenv("PBCALC_DEFAULTS_UNITS", "in")
+
+	got, err := readSpecSettings(NewTagCommand())
+	require.NoError(t, err)
+	assert.Equal(t, specSettings{Board: calculate.BoardStandard, Unit: calculate.UnitImperial, Precision: 1}, got)
+
+	assert.Error(t, NewTagCommand().Flags().Set("board", "mini"))
+}
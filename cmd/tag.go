@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+const tagCommandLongDesc = `Calculate the corner cuts and string hole for a gift tag.
+
+With --corner both top corners are punched off. The string hole is centred
+across the tag.
+
+  pbc tag --width 2 --height 3.5 --corner --units in`
+
+// NewTagCommand returns a new tag command.
+func NewTagCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "calculate the corner cuts and string hole for a gift tag",
+		Long:  tagCommandLongDesc,
+		Args:  cobra.NoArgs,
+		RunE:  RunTagCmd,
+	}
+
+	cmd.Flags().Float64P("width", "w", 0, "width of the tag")
+	cmd.Flags().Float64("height", 0, "height of the tag")
+	cmd.Flags().Bool("corner", false, "punch off both top corners")
+	addUnitFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewTagCommand())
+}
+
+// RunTagCmd is the entrypoint for the tag command.
+func RunTagCmd(cmd *cobra.Command, args []string) error {
+	width, err := cmd.Flags().GetFloat64("width")
+	if err != nil {
+		return err
+	}
+
+	height, err := cmd.Flags().GetFloat64("height")
+	if err != nil {
+		return err
+	}
+
+	corner, err := cmd.Flags().GetBool("corner")
+	if err != nil {
+		return err
+	}
+
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	tag, err := calculate.TagSpec{
+		Width:  width,
+		Height: height,
+		Corner: corner,
+		Unit:   settings.Unit,
+	}.Calculate()
+	if err != nil {
+		return err
+	}
+
+	recordCalculation(cmd, settings, map[string]float64{"width": width, "height": height}, tag)
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), tag)
+	}
+
+	p := settings.Precision
+
+	cmd.Printf("Tag: %0.*f x %0.*f\n", p, tag.Size.Length, p, tag.Size.Width)
+
+	if tag.CornerCut > 0 {
+		cmd.Printf("Corner cuts: %0.*f along each edge from both top corners\n", p, tag.CornerCut)
+	}
+
+	cmd.Printf("String hole: centred, %0.*f from the top\n", p, tag.HoleOffset)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestNewTagCommand(t *testing.T) {
+	got := NewTagCommand()
+
+	assert.Equal(t, "tag", got.Name())
+	assert.True(t, got.Runnable())
+}
+
+func TestRunTagCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "punched corners",
+			flags: map[string]string{"width": "2", "height": "3.5", "corner": "true", "units": "in", "precision": "3"},
+			want: "Tag: 2.000 x 3.500\n" +
+				"Corner cuts: 0.500 along each edge from both top corners\n" +
+				"String hole: centred, 0.375 from the top\n",
+		},
+		{
+			name:  "square corners",
+			flags: map[string]string{"width": "5", "height": "8"},
+			want:  "Tag: 5.0 x 8.0\nString hole: centred, 1.0 from the top\n",
+		},
+		{
+			name:    "too narrow for punched corners",
+			flags:   map[string]string{"width": "2", "height": "8", "corner": "true"},
+			wantErr: calculate.ErrInvalidDimension,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewTagCommand()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+
+			for k, v := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(k, v))
+			}
+
+			err := RunTagCmd(cmd, nil)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				assert.Empty(t, out.String())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
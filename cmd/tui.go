@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/tui"
+)
+
+const tuiCommandLongDesc = `Start a full-screen calculator that recalculates the envelope on every keystroke.
+
+Type the content length and width, use tab to move between fields, and toggle
+the board (b), units (u) and content fit (c). The result can be copied to the
+clipboard (y) or the layout exported as ` + tui.SVGFileName + ` (s).`
+
+// NewTUICommand returns a new tui command.
+func NewTUICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "interactive envelope calculator",
+		Long:  tuiCommandLongDesc,
+		Args:  cobra.NoArgs,
+		RunE:  RunTUICmd,
+	}
+
+	cmd.Flags().Float64P("length", "l", 0, "initial length of envelope")
+	cmd.Flags().Float64P("width", "w", 0, "initial width of envelope")
+	addSpecFlags(cmd)
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(NewTUICommand())
+}
+
+// RunTUICmd is the entrypoint for the tui command.
+func RunTUICmd(cmd *cobra.Command, args []string) error {
+	length, err := cmd.Flags().GetFloat64("length")
+	if err != nil {
+		return err
+	}
+
+	width, err := cmd.Flags().GetFloat64("width")
+	if err != nil {
+		return err
+	}
+
+	settings, err := readSpecSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	m := tui.New(calculate.EnvelopeSpec{
+		Length: length,
+		Width:  width,
+		Loose:  settings.Loose,
+		Board:  settings.Board,
+		Unit:   settings.Unit,
+	}, settings.Precision)
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithInput(cmd.InOrStdin()), tea.WithOutput(cmd.OutOrStdout()))
+	if _, err := p.Run(); err != nil {
+		return failure(err)
+	}
+
+	return nil
+}
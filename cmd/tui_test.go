@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTUICommand(t *testing.T) {
+	got := NewTUICommand()
+
+	assert.Equal(t, "tui", got.Name())
+	assert.True(t, got.Runnable())
+}
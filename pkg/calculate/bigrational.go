@@ -0,0 +1,218 @@
+package calculate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BigRational is an arbitrary-precision rational number backed by big.Rat. It
+// has the same methods and text forms as Rational, but never overflows. The
+// zero value is 0.
+//
+// A BigRational is immutable: every operation returns a new value, so values can
+// be copied and shared freely.
+type BigRational struct {
+	r *big.Rat
+}
+
+// NewBigRational returns the BigRational with the same value as r.
+func NewBigRational(r Rational) BigRational {
+	p, q := r.fraction()
+
+	return BigRational{big.NewRat(p, q)}
+}
+
+// rat returns the value of x, which must not be modified.
+func (x BigRational) rat() *big.Rat {
+	if x.r == nil {
+		return new(big.Rat)
+	}
+
+	return x.r
+}
+
+// Rational returns x as a Rational, or an error wrapping ErrRange if its
+// numerator or denominator does not fit in an int64.
+func (x BigRational) Rational() (Rational, error) {
+	r := x.rat()
+	if !r.Num().IsInt64() || !r.Denom().IsInt64() {
+		return Rational{}, fmt.Errorf("%w: %s", ErrRange, x.Mixed())
+	}
+
+	return newFraction(r.Num().Int64(), r.Denom().Int64()), nil
+}
+
+// Add returns x + y.
+func (x BigRational) Add(y BigRational) BigRational {
+	return BigRational{new(big.Rat).Add(x.rat(), y.rat())}
+}
+
+// Sub returns x - y.
+func (x BigRational) Sub(y BigRational) BigRational {
+	return BigRational{new(big.Rat).Sub(x.rat(), y.rat())}
+}
+
+// Mul returns x × y.
+func (x BigRational) Mul(y BigRational) BigRational {
+	return BigRational{new(big.Rat).Mul(x.rat(), y.rat())}
+}
+
+// Neg returns -x.
+func (x BigRational) Neg() BigRational {
+	return BigRational{new(big.Rat).Neg(x.rat())}
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of x.
+func (x BigRational) Sign() int {
+	return x.rat().Sign()
+}
+
+// Cmp returns -1, 0 or +1 depending on whether x is less than, equal to or greater than y.
+func (x BigRational) Cmp(y BigRational) int {
+	return x.rat().Cmp(y.rat())
+}
+
+// Float64 returns the nearest float64 value to x.
+func (x BigRational) Float64() float64 {
+	f, _ := x.rat().Float64()
+
+	return f
+}
+
+// String returns x in the same form as Rational.String, such as "1 + 1/2".
+func (x BigRational) String() string {
+	return x.format(" + ")
+}
+
+// Mixed returns x as a mixed number, such as "1 1/2", "-3/4" or "2".
+func (x BigRational) Mixed() string {
+	return x.format(" ")
+}
+
+// format writes x as a mixed number with sep between the whole part and the fraction.
+func (x BigRational) format(sep string) string {
+	r := x.rat()
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	num := new(big.Int).Abs(r.Num())
+	whole, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+
+	var s string
+	if r.Sign() < 0 {
+		s = "-"
+	}
+
+	if whole.Sign() != 0 {
+		s += whole.String() + sep
+	}
+
+	return s + rem.String() + "/" + r.Denom().String()
+}
+
+// ParseBigRational parses any of the forms read by ParseRational into a BigRational,
+// without limiting the number of digits.
+func ParseBigRational(s string) (BigRational, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return BigRational{}, fmt.Errorf("%w: empty string", ErrInvalidRational)
+	}
+
+	if !strings.Contains(s, "/") {
+		x, err := ParseBigDecimal(s)
+		if err != nil {
+			return BigRational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+		}
+
+		return x, nil
+	}
+
+	whole, frac, ok := splitMixed(s)
+
+	// big.Rat also reads base prefixes and underscores, which are not allowed here
+	if !ok || strings.Trim(frac, "0123456789/-") != "" {
+		return BigRational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+	}
+
+	f, ok := new(big.Rat).SetString(frac)
+	if !ok {
+		return BigRational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+	}
+
+	if whole == "" {
+		return BigRational{f}, nil
+	}
+
+	w, ok := new(big.Int).SetString(whole, 10)
+	if !ok {
+		return BigRational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+	}
+
+	if f.Sign() < 0 || f.Cmp(big.NewRat(1, 1)) >= 0 {
+		return BigRational{}, fmt.Errorf("%w: %q: fraction must be proper and not negative", ErrInvalidRational, s)
+	}
+
+	// the sign of the whole part applies to the whole number
+	r := new(big.Rat).Add(new(big.Rat).SetInt(w.Abs(w)), f)
+	if strings.HasPrefix(whole, "-") {
+		r.Neg(r)
+	}
+
+	return BigRational{r}, nil
+}
+
+// MarshalText implements encoding.TextMarshaler using the mixed number form.
+func (x BigRational) MarshalText() ([]byte, error) {
+	return []byte(x.Mixed()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts any form read by ParseBigRational.
+func (x *BigRational) UnmarshalText(text []byte) error {
+	v, err := ParseBigRational(string(text))
+	if err != nil {
+		return err
+	}
+
+	*x = v
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The value is written as a mixed number string.
+func (x BigRational) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.Mixed())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a mixed number string or a JSON number.
+func (x *BigRational) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return x.UnmarshalText([]byte(s))
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRational, data)
+	}
+
+	return x.UnmarshalText([]byte(num))
+}
+
+// MarshalYAML implements yaml.Marshaler using the mixed number form.
+func (x BigRational) MarshalYAML() (any, error) {
+	return x.Mixed(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. It accepts a mixed number or a decimal.
+func (x *BigRational) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%w: line %d: expected a single value", ErrInvalidRational, node.Line)
+	}
+
+	return x.UnmarshalText([]byte(node.Value))
+}
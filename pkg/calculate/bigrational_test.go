@@ -0,0 +1,215 @@
+package calculate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestBigRational_Arithmetic(t *testing.T) {
+	half := newFraction(1, 2)
+	third := newFraction(1, 3)
+	negSixFifths := newFraction(-6, 5)
+
+	big := func(r Rational) BigRational { return NewBigRational(r) }
+	mixed := func(r Rational, err error) string {
+		assert.NoError(t, err)
+		return r.Mixed()
+	}
+
+	// every operation agrees with Rational
+	assert.Equal(t, mixed(half.Add(third)), big(half).Add(big(third)).Mixed())
+	assert.Equal(t, mixed(half.Sub(third)), big(half).Sub(big(third)).Mixed())
+	assert.Equal(t, mixed(half.Mul(negSixFifths)), big(half).Mul(big(negSixFifths)).Mixed())
+	assert.Equal(t, mixed(negSixFifths.Neg()), big(negSixFifths).Neg().Mixed())
+	assert.Equal(t, half.Cmp(third), big(half).Cmp(big(third)))
+	assert.Equal(t, negSixFifths.Sign(), big(negSixFifths).Sign())
+	assert.Equal(t, negSixFifths.Float64(), big(negSixFifths).Float64())
+
+	var zero BigRational
+	assert.Equal(t, 0, zero.Sign())
+	assert.Equal(t, "1/2", zero.Add(big(half)).Mixed())
+}
+
+func TestBigRational_String(t *testing.T) {
+	values := []Rational{{}, newFraction(3, 1), newFraction(3, 4), newFraction(3, 2), newFraction(-3, 4), newFraction(-6, 5)}
+
+	for _, r := range values {
+		assert.Equal(t, r.String(), NewBigRational(r).String())
+		assert.Equal(t, r.Mixed(), NewBigRational(r).Mixed())
+	}
+}
+
+func TestBigRational_NoOverflow(t *testing.T) {
+	// squaring a sum of tiny fractions overflows int64 but not BigRational
+	x, err := ParseBigRational("1/12157665459056928801")
+	assert.NoError(t, err)
+
+	sum := x.Add(x).Add(x).Mul(x.Add(x).Add(x))
+	assert.Equal(t, "1/16423203268260658146231467800709255289", sum.Mixed())
+
+	_, err = sum.Rational()
+	assert.ErrorIs(t, err, ErrRange)
+
+	r, err := NewBigRational(newFraction(-6, 5)).Rational()
+	assert.NoError(t, err)
+	assert.Equal(t, newFraction(-6, 5), r)
+}
+
+func TestParseBigDecimal(t *testing.T) {
+	got, err := ParseBigDecimal("0.1234567890123456789012345")
+	assert.NoError(t, err)
+	assert.Equal(t, "246913578024691357802469/2000000000000000000000000", got.Mixed())
+
+	got, err = ParseBigDecimal("-1,234.5e-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "-123 9/20", got.Mixed())
+
+	_, err = ParseBigDecimal("1e100000")
+	assert.ErrorIs(t, err, ErrRange)
+
+	_, err = ParseBigDecimal("1.2.3")
+	assert.ErrorIs(t, err, ErrSyntax)
+}
+
+func TestParseBigRational(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "whole", arg: "2", want: "2", assertion: assert.NoError},
+		{name: "mixed", arg: " 1 1/2 ", want: "1 1/2", assertion: assert.NoError},
+		{name: "string form", arg: "1 + 1/2", want: "1 1/2", assertion: assert.NoError},
+		{name: "negative mixed", arg: "-1 1/5", want: "-1 1/5", assertion: assert.NoError},
+		{name: "negative proper", arg: "-3/4", want: "-3/4", assertion: assert.NoError},
+		{name: "large", arg: "123456789012345678901 1/3", want: "123456789012345678901 1/3", assertion: assert.NoError},
+		{name: "empty", arg: "", assertion: assert.Error},
+		{name: "base prefix", arg: "0x10/3", assertion: assert.Error},
+		{name: "zero denominator", arg: "1/0", assertion: assert.Error},
+		{name: "improper mixed", arg: "1 3/2", assertion: assert.Error},
+		{name: "negative fraction", arg: "1 -1/2", assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBigRational(tt.arg)
+
+			tt.assertion(t, err)
+
+			if err == nil {
+				assert.Equal(t, tt.want, got.Mixed())
+			}
+		})
+	}
+}
+
+func TestBigRational_RoundTrip(t *testing.T) {
+	x, err := ParseBigRational("-98765432109876543210 7/16")
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(x)
+	assert.NoError(t, err)
+	assert.Equal(t, `"-98765432109876543210 7/16"`, string(data))
+
+	var fromJSON BigRational
+	assert.NoError(t, json.Unmarshal(data, &fromJSON))
+	assert.Equal(t, 0, x.Cmp(fromJSON))
+
+	assert.NoError(t, json.Unmarshal([]byte("0.4375"), &fromJSON))
+	assert.Equal(t, "7/16", fromJSON.Mixed())
+
+	data, err = yaml.Marshal(x)
+	assert.NoError(t, err)
+
+	var fromYAML BigRational
+	assert.NoError(t, yaml.Unmarshal(data, &fromYAML))
+	assert.Equal(t, 0, x.Cmp(fromYAML))
+}
+
+// The benchmarks below compare Rational and BigRational on the work done for a
+// typical envelope: reading the measurements, summing margins and dimensions,
+// and comparing the result with a board limit.
+
+var benchmarkInputs = []string{"12.7", "17.78", "1.1", "30.5"}
+
+func BenchmarkParseDecimal(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, s := range benchmarkInputs {
+			if _, err := ParseDecimal(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseBigDecimal(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, s := range benchmarkInputs {
+			if _, err := ParseBigDecimal(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkRational_Envelope(b *testing.B) {
+	var v [4]Rational
+	for i, s := range benchmarkInputs {
+		v[i], _ = ParseDecimal(s)
+	}
+
+	length, width, margin, limit := v[0], v[1], v[2], v[3]
+	two, half := newFraction(2, 1), newFraction(1, 2)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		margins, _ := margin.Mul(two)
+		sides, _ := length.Add(width)
+		dist, _ := sides.Mul(half)
+		paper, _ := margins.Add(dist)
+		_ = paper.Cmp(limit)
+	}
+}
+
+func BenchmarkBigRational_Envelope(b *testing.B) {
+	var v [4]BigRational
+	for i, s := range benchmarkInputs {
+		v[i], _ = ParseBigDecimal(s)
+	}
+
+	length, width, margin, limit := v[0], v[1], v[2], v[3]
+	two, half := NewBigRational(newFraction(2, 1)), NewBigRational(newFraction(1, 2))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		paper := margin.Mul(two).Add(length.Add(width).Mul(half))
+		_ = paper.Cmp(limit)
+	}
+}
+
+func BenchmarkRational_Sum(b *testing.B) {
+	sixteenth := newFraction(1, 16)
+
+	for i := 0; i < b.N; i++ {
+		var sum Rational
+		for j := 0; j < 100; j++ {
+			sum, _ = sum.Add(sixteenth)
+		}
+	}
+}
+
+func BenchmarkBigRational_Sum(b *testing.B) {
+	sixteenth := NewBigRational(newFraction(1, 16))
+
+	for i := 0; i < b.N; i++ {
+		var sum BigRational
+		for j := 0; j < 100; j++ {
+			sum = sum.Add(sixteenth)
+		}
+	}
+}
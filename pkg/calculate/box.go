@@ -0,0 +1,181 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+)
+
+// BoxSpec describes a box folded from a square of paper, and optionally its lid.
+type BoxSpec struct {
+	Length float64 `json:"length"` // inside length of the box
+	Width  float64 `json:"width"`  // inside width of the box
+	Height float64 `json:"height"` // height of the box sides
+	Board  Board   `json:"board"`
+	Unit   Unit    `json:"unit"`
+
+	Lid       bool     `json:"lid,omitempty"`
+	LidHeight float64  `json:"lid_height,omitempty"` // height of the lid sides; zero for the box height
+	Clearance *float64 `json:"clearance,omitempty"`  // room left between the box and each lid side; nil for LidClearance
+	Steps     bool     `json:"steps,omitempty"`      // give step-by-step board instructions for each part
+
+	// Items are the pieces of content stacked in the box. When there are any,
+	// the length and width are those of the stack, and the height is its
+	// thickness unless a height is given.
+	Items []ContentItem `json:"items,omitempty"`
+}
+
+// BoxPart is the paper size and punch points for a box or its lid.
+type BoxPart struct {
+	Length        float64 `json:"length"`
+	Width         float64 `json:"width"`
+	Height        float64 `json:"height"`
+	PaperSize     float64 `json:"paper_size"`     // side of the square paper
+	PunchLocation float64 `json:"punch_location"` // first punch position along the paper edge
+	SecondPunch   float64 `json:"second_punch"`   // second punch position along the same edge
+
+	Instructions *Instructions `json:"instructions,omitempty"` // board instructions, if the spec asks for them
+}
+
+// Box is the result of a box calculation.
+type Box struct {
+	Spec      BoxSpec  `json:"spec"`
+	Stack     *Stack   `json:"stack,omitempty"` // stacked content, if the spec has items
+	Margin    float64  `json:"margin"`
+	Clearance float64  `json:"clearance,omitempty"` // clearance used for the lid
+	Base      BoxPart  `json:"base"`
+	Lid       *BoxPart `json:"lid,omitempty"`
+}
+
+// LidClearance returns the default room left between a box and each side of its lid.
+func LidClearance(unit Unit) float64 {
+	if unit == UnitImperial {
+		return 0.125
+	}
+
+	return 0.3
+}
+
+// Validate reports whether the spec can be used for a calculation.
+func (s BoxSpec) Validate() error {
+	if s.Length <= 0 {
+		return fmt.Errorf("%w: length must be greater than zero, got %g", ErrInvalidDimension, s.Length)
+	}
+
+	if s.Width <= 0 {
+		return fmt.Errorf("%w: width must be greater than zero, got %g", ErrInvalidDimension, s.Width)
+	}
+
+	if s.Height <= 0 {
+		return fmt.Errorf("%w: height must be greater than zero, got %g", ErrInvalidDimension, s.Height)
+	}
+
+	if s.LidHeight < 0 {
+		return fmt.Errorf("%w: lid height must not be negative, got %g", ErrInvalidDimension, s.LidHeight)
+	}
+
+	if s.Clearance != nil && *s.Clearance < 0 {
+		return fmt.Errorf("%w: clearance must not be negative, got %g", ErrInvalidDimension, *s.Clearance)
+	}
+
+	return nil
+}
+
+// withStack returns the spec with the size its items need, and their stack, or
+// the spec unchanged if it has no items.
+func (s BoxSpec) withStack() (BoxSpec, *Stack, error) {
+	if len(s.Items) == 0 {
+		return s, nil, nil
+	}
+
+	st, err := NewStack(s.Items)
+	if err != nil {
+		return s, nil, err
+	}
+
+	s.Length, s.Width = st.Length, st.Width
+	if s.Height == 0 {
+		s.Height = st.Thickness
+	}
+
+	return s, &st, nil
+}
+
+// Calculate calculates the paper size and punch points for a box and, if the
+// spec has one, its lid. The lid is larger than the box by the clearance on
+// every side.
+func (s BoxSpec) Calculate() (Box, error) {
+	s, stack, err := s.withStack()
+	if err != nil {
+		return Box{}, err
+	}
+
+	if err := s.Validate(); err != nil {
+		return Box{}, err
+	}
+
+	// boxes use the normal margin for the board, as the original calculator does
+	margin := Margin(s.Unit, s.Board, false)
+
+	box := Box{Spec: s, Stack: stack, Margin: margin}
+
+	base, err := s.part(s.Length, s.Width, s.Height, margin)
+	if err != nil {
+		return Box{}, err
+	}
+
+	box.Base = base
+
+	if !s.Lid {
+		return box, nil
+	}
+
+	box.Clearance = LidClearance(s.Unit)
+	if s.Clearance != nil {
+		box.Clearance = *s.Clearance
+	}
+
+	height := s.LidHeight
+	if height == 0 {
+		height = s.Height
+	}
+
+	lid, err := s.part(s.Length+2*box.Clearance, s.Width+2*box.Clearance, height, margin)
+	if err != nil {
+		return Box{}, err
+	}
+
+	box.Lid = &lid
+
+	return box, nil
+}
+
+// part calculates one box part. The content sits diagonally on the paper as
+// for an envelope, with the sides folded up around it, so the paper grows by
+// twice the projected height and the second punch is that much further along.
+func (s BoxSpec) part(length, width, height, margin float64) (BoxPart, error) {
+	dist1 := length * math.Sqrt(0.5)
+	dist2 := width * math.Sqrt(0.5)
+	dist3 := height * math.Sqrt(0.5)
+
+	punch := margin + math.Min(dist1, dist2)
+
+	p := BoxPart{
+		Length:        length,
+		Width:         width,
+		Height:        height,
+		PaperSize:     dist1 + dist2 + 2*(dist3+margin),
+		PunchLocation: punch,
+		SecondPunch:   punch + 2*dist3,
+	}
+
+	if limit := MaxPaperSize(s.Unit, s.Board); p.PaperSize > limit {
+		return BoxPart{}, fmt.Errorf("%w: paper size %0.2f %s is larger than the %s board limit of %g %s",
+			ErrBoardLimit, p.PaperSize, s.Unit, s.Board, limit, s.Unit)
+	}
+
+	if s.Steps {
+		p.Instructions = boxInstructions(p)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,118 @@
+package calculate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearance returns a pointer to a lid clearance.
+func clearance(c float64) *float64 {
+	return &c
+}
+
+func TestBoxSpec_Calculate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    BoxSpec
+		want    Box
+		wantErr error
+	}{
+		{
+			name: "box only",
+			spec: BoxSpec{Length: 4, Width: 3, Height: 1, Unit: UnitImperial},
+			want: Box{
+				Margin: 0.4375,
+				Base:   BoxPart{Length: 4, Width: 3, Height: 1, PaperSize: 7.2389, PunchLocation: 2.5588, SecondPunch: 3.9730},
+			},
+		},
+		{
+			name: "lid with clearance and height",
+			spec: BoxSpec{Length: 4, Width: 3, Height: 1, Unit: UnitImperial, Lid: true, LidHeight: 1.5, Clearance: clearance(0.125)},
+			want: Box{
+				Margin:    0.4375,
+				Clearance: 0.125,
+				Base:      BoxPart{Length: 4, Width: 3, Height: 1, PaperSize: 7.2389, PunchLocation: 2.5588, SecondPunch: 3.9730},
+				Lid:       &BoxPart{Length: 4.25, Width: 3.25, Height: 1.5, PaperSize: 8.2996, PunchLocation: 2.7356, SecondPunch: 4.8569},
+			},
+		},
+		{
+			name: "lid with defaults",
+			spec: BoxSpec{Length: 8, Width: 6, Height: 2, Lid: true},
+			want: Box{
+				Margin:    1.1,
+				Clearance: 0.3,
+				Base:      BoxPart{Length: 8, Width: 6, Height: 2, PaperSize: 14.9279, PunchLocation: 5.3426, SecondPunch: 8.1711},
+				Lid:       &BoxPart{Length: 8.6, Width: 6.6, Height: 2, PaperSize: 15.7765, PunchLocation: 5.7669, SecondPunch: 8.5953},
+			},
+		},
+		{
+			name: "lid without clearance",
+			spec: BoxSpec{Length: 8, Width: 6, Height: 2, Lid: true, Clearance: clearance(0)},
+			want: Box{
+				Margin: 1.1,
+				Base:   BoxPart{Length: 8, Width: 6, Height: 2, PaperSize: 14.9279, PunchLocation: 5.3426, SecondPunch: 8.1711},
+				Lid:    &BoxPart{Length: 8, Width: 6, Height: 2, PaperSize: 14.9279, PunchLocation: 5.3426, SecondPunch: 8.1711},
+			},
+		},
+		{
+			name:    "zero height",
+			spec:    BoxSpec{Length: 8, Width: 6},
+			wantErr: errors.New("invalid dimension: height must be greater than zero, got 0"),
+		},
+		{
+			name:    "negative clearance",
+			spec:    BoxSpec{Length: 8, Width: 6, Height: 2, Lid: true, Clearance: clearance(-0.1)},
+			wantErr: ErrInvalidDimension,
+		},
+		{
+			name:    "box too big",
+			spec:    BoxSpec{Length: 16, Width: 12, Height: 7},
+			wantErr: ErrBoardLimit,
+		},
+		{
+			name:    "lid too big",
+			spec:    BoxSpec{Length: 6, Width: 5, Height: 1, Unit: UnitImperial, Lid: true, LidHeight: 2.5},
+			wantErr: ErrBoardLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Calculate()
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.spec, got.Spec)
+			assert.Equal(t, tt.want.Margin, got.Margin)
+			assert.Equal(t, tt.want.Clearance, got.Clearance)
+			assertBoxPart(t, tt.want.Base, got.Base)
+
+			if tt.want.Lid == nil {
+				assert.Nil(t, got.Lid)
+				return
+			}
+
+			require.NotNil(t, got.Lid)
+			assertBoxPart(t, *tt.want.Lid, *got.Lid)
+		})
+	}
+}
+
+func assertBoxPart(t *testing.T, want, got BoxPart) {
+	t.Helper()
+
+	const delta = 0.0001
+
+	assert.InDelta(t, want.Length, got.Length, 1e-9)
+	assert.InDelta(t, want.Width, got.Width, 1e-9)
+	assert.InDelta(t, want.Height, got.Height, 1e-9)
+	assert.InDelta(t, want.PaperSize, got.PaperSize, delta)
+	assert.InDelta(t, want.PunchLocation, got.PunchLocation, delta)
+	assert.InDelta(t, want.SecondPunch, got.SecondPunch, delta)
+}
@@ -2,10 +2,8 @@
 package calculate
 
 import (
-	"fmt"
 	"math"
 	"strconv"
-	"strings"
 )
 
 func gcd(a, b int64) int64 {
@@ -60,71 +58,6 @@ func (r Rational) String() string {
 	return s
 }
 
-// ParseDecimal parses a decimal string into a rational number.
-func ParseDecimal(s string) (r Rational, err error) {
-	const (
-		bitSize int = 64
-		base10  int = 10
-	)
-
-	sign := int64(1)
-
-	if strings.HasPrefix(s, "-") {
-		sign = -1
-	}
-
-	p := strings.IndexByte(s, '.')
-
-	if p < 0 {
-		p = len(s)
-	}
-
-	if i := s[:p]; len(i) > 0 {
-		if i != "+" && i != "-" {
-			r.i, err = strconv.ParseInt(i, 10, 64)
-			if err != nil {
-				return Rational{}, err
-			}
-		} else {
-			r.i = 0
-		}
-	}
-	if p >= len(s) {
-		p = len(s) - 1
-	}
-
-	if f := s[p+1:]; len(f) > 0 {
-		n, err := strconv.ParseUint(f, base10, bitSize)
-		if err != nil {
-			return Rational{}, err
-		}
-
-		d := math.Pow10(len(f))
-
-		if math.Log2(d) > float64(bitSize-1) {
-			err = fmt.Errorf("ParseDecimal: parsing %q: value out of range", f)
-			return Rational{}, err
-		}
-
-		r.n = int64(n)
-
-		if r.n != 0 {
-			r.d = int64(d)
-		}
-
-		if g := gcd(r.n, r.d); g != 0 {
-			r.n /= g
-			r.d /= g
-		}
-
-		if r.i == 0 {
-			r.n *= sign
-		}
-	}
-
-	return r, nil
-}
-
 // Math.fraction= function(n, prec, up){
 // 		var s= String(n),
 // 	p= s.indexOf('.');
@@ -145,24 +78,38 @@ func ParseDecimal(s string) (r Rational, err error) {
 
 // CalculateEnvelope calculates the paper size and punch location for an envelope.
 func CalculateEnvelope(length, width float64, isLoose bool, boardMini bool) (float64, float64, error) {
-	const (
-		marginMetric            float64 = 1.1
-		marginMetricLoose       float64 = 1.5
-		marginImperial          float64 = 0.4375
-		marginImperialLoose     float64 = 0.625
-		marginMiniMetric        float64 = 0.67
-		marginMiniMetricLoose   float64 = 0.914
-		marginMiniImperial      float64 = 0.25
-		marginMiniImperialLoose float64 = 0.34
-		metricUnit              string  = "cm"
-		imperialUnit            string  = "inch"
-	)
-
-	margin := 1.1 // TODO: figure out how to incorporate mini board values
-	if isLoose {
-		margin += 0.4
+	board := BoardStandard
+	if boardMini {
+		board = BoardMini
+	}
+
+	env, err := EnvelopeSpec{
+		Length: length,
+		Width:  width,
+		Loose:  isLoose,
+		Board:  board,
+		Unit:   UnitMetric,
+	}.Calculate()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return env.PaperSize, env.PunchLocation, nil
+}
+
+// Calculate calculates the paper size and punch location for an envelope.
+func (s EnvelopeSpec) Calculate() (Envelope, error) {
+	s, stack, err := s.withStack()
+	if err != nil {
+		return Envelope{}, err
 	}
 
+	if err := s.Validate(); err != nil {
+		return Envelope{}, err
+	}
+
+	margin, allowance, compensation := s.margin()
+
 	//   var isNotThick = $("#cardsizeb").is(":checked") || $("#cardsizec").is(":checked")
 	//   var margin = isMini
 	// 	? (isNotThick
@@ -180,9 +127,8 @@ func CalculateEnvelope(length, width float64, isLoose bool, boardMini bool) (flo
 	//   var slWidth = GetNumericValue($("#cardsizeWidth").val());
 	//   var slHeight = GetNumericValue($("#cardsizeHeight").val());
 
-	const distMultiplier float64 = 0.707106781187 // 1/sqrt(2)
-	dist1 := length * distMultiplier
-	dist2 := width * distMultiplier
+	dist1 := s.Length * math.Sqrt(0.5)
+	dist2 := s.Width * math.Sqrt(0.5)
 
 	//   var dist1 = slLength * Math.sqrt(0.5);
 	//   var dist2 = slWidth * Math.sqrt(0.5);
@@ -356,7 +302,12 @@ func CalculateEnvelope(length, width float64, isLoose bool, boardMini bool) (flo
 	// 	  }
 	// 	  else
 	// 	  {
-	punch := margin + math.Min(dist1, dist2)
+	orientation := s.orientation()
+
+	punch := margin + dist2
+	if orientation == OrientationLength {
+		punch = margin + dist1
+	}
 
 	// 		  if ( dist2 > dist1 )
 	// 		  {
@@ -429,5 +380,43 @@ func CalculateEnvelope(length, width float64, isLoose bool, boardMini bool) (flo
 	//    });
 	//   CalculateSizes();
 	// });
-	return paper, punch, nil
+	if paper > MaxPaperSize(s.Unit, s.Board) {
+		return Envelope{}, s.boardLimitError(paper)
+	}
+
+	env := Envelope{
+		Spec:             s,
+		Stack:            stack,
+		Margin:           margin,
+		LengthProjection: dist1,
+		WidthProjection:  dist2,
+		PaperSize:        paper,
+		PunchLocation:    punch,
+		Orientation:      orientation,
+		FoldAllowance:    allowance,
+		FoldCompensation: compensation,
+	}
+
+	// the stock size comes first, since everything cut from the paper uses it
+	stock, err := newStockFit(env)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	env.Stock = stock
+
+	if env.Liner, err = newLiner(env); err != nil {
+		return Envelope{}, err
+	}
+
+	if env.Inserts, err = newInserts(env); err != nil {
+		return Envelope{}, err
+	}
+
+	env.Flaps = newFlapAnalysis(env)
+
+	env.Instructions = newInstructions(env)
+	env.Sensitivity = newSensitivity(env)
+
+	return env, nil
 }
@@ -45,138 +45,81 @@ func TestCalculateEnvelope(t *testing.T) {
 			wantPunchLocation: 7.16,
 			assertion:         assert.NoError,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotPaperSize, gotPunchLocation, err := CalculateEnvelope(tt.args.length, tt.args.width, tt.args.isLoose, tt.args.boardMini)
-
-			tt.assertion(t, err)
-
-			if err == nil {
-				assert.InDelta(t, tt.wantPaperSize, gotPaperSize, 0.01)
-				assert.InDelta(t, tt.wantPunchLocation, gotPunchLocation, 0.01)
-			}
-		})
-	}
-}
-
-func TestParseDecimal(t *testing.T) {
-	tests := []struct {
-		name      string
-		arg       string
-		wantR     Rational
-		assertion assert.ErrorAssertionFunc
-	}{
-		{
-			name:      "empty string",
-			arg:       "",
-			wantR:     Rational{},
-			assertion: assert.NoError,
-		},
-		{
-			name: "integer",
-			arg:  "1",
-			wantR: Rational{
-				i: 1,
-				n: 0,
-				d: 0,
-			},
-			assertion: assert.NoError,
-		},
-		{
-			name: "zero",
-			arg:  "0",
-			wantR: Rational{
-				i: 0,
-				n: 0,
-				d: 0,
-			},
-			assertion: assert.NoError,
-		},
-		{
-			name: "neg zero",
-			arg:  "-0",
-			wantR: Rational{
-				i: 0,
-				n: 0,
-				d: 0,
-			},
-			assertion: assert.NoError,
-		},
 		{
-			name: "int - no fraction",
-			arg:  "1.0",
-			wantR: Rational{
-				i: 1,
-				n: 0,
-				d: 0,
-			},
-			assertion: assert.NoError,
-		},
-		{
-			name: "int - fraction",
-			arg:  "1.1",
-			wantR: Rational{
-				i: 1,
-				n: 1,
-				d: 10,
+			name: "10x8 - snug",
+			args: args{
+				length:    10,
+				width:     8,
+				isLoose:   false,
+				boardMini: false,
 			},
-			assertion: assert.NoError,
+			wantPaperSize:     14.93,
+			wantPunchLocation: 6.76,
+			assertion:         assert.NoError,
 		},
 		{
-			name: "int - simplify fraction",
-			arg:  "1.2",
-			wantR: Rational{
-				i: 1,
-				n: 1,
-				d: 5,
+			name: "10x8 - snug mini",
+			args: args{
+				length:    10,
+				width:     8,
+				isLoose:   false,
+				boardMini: true,
 			},
-			assertion: assert.NoError,
+			wantPaperSize:     14.07,
+			wantPunchLocation: 6.33,
+			assertion:         assert.NoError,
 		},
 		{
-			name: "neg int - simplify fraction",
-			arg:  "-1.2",
-			wantR: Rational{
-				i: -1,
-				n: 1,
-				d: 5,
+			name: "10x8 - loose mini",
+			args: args{
+				length:    10,
+				width:     8,
+				isLoose:   true,
+				boardMini: true,
 			},
-			assertion: assert.NoError,
+			wantPaperSize:     14.56,
+			wantPunchLocation: 6.57,
+			assertion:         assert.NoError,
 		},
 		{
-			name: "neg integer",
-			arg:  "-3",
-			wantR: Rational{
-				i: -3,
-				n: 0,
-				d: 0,
+			name: "zero length",
+			args: args{
+				length:    0,
+				width:     8,
+				isLoose:   false,
+				boardMini: false,
 			},
-			assertion: assert.NoError,
+			assertion: invalidDimension,
 		},
 		{
-			name: "neg - no leading int",
-			arg:  "-.2",
-			wantR: Rational{
-				i: 0,
-				n: -1,
-				d: 5,
+			name: "negative width",
+			args: args{
+				length:    10,
+				width:     -8,
+				isLoose:   false,
+				boardMini: false,
 			},
-			assertion: assert.NoError,
+			assertion: invalidDimension,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotR, err := ParseDecimal(tt.arg)
+			gotPaperSize, gotPunchLocation, err := CalculateEnvelope(tt.args.length, tt.args.width, tt.args.isLoose, tt.args.boardMini)
 
 			tt.assertion(t, err)
 
 			if err == nil {
-				assert.Equal(t, tt.wantR, gotR)
+				assert.InDelta(t, tt.wantPaperSize, gotPaperSize, 0.01)
+				assert.InDelta(t, tt.wantPunchLocation, gotPunchLocation, 0.01)
 			}
 		})
 	}
 }
 
+func invalidDimension(t assert.TestingT, err error, msgAndArgs ...interface{}) bool {
+	return assert.ErrorIs(t, err, ErrInvalidDimension, msgAndArgs...)
+}
+
 func Test_gcd(t *testing.T) {
 	type args struct {
 		a int64
@@ -0,0 +1,136 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// PaperWeight is the weight and thickness of a paper or cardstock.
+type PaperWeight struct {
+	Name    string  `json:"name,omitempty"`
+	GSM     float64 `json:"gsm,omitempty"` // grams per square metre
+	Caliper float64 `json:"caliper"`       // thickness in millimetres
+}
+
+func (p PaperWeight) String() string {
+	if p.Name != "" {
+		return p.Name
+	}
+
+	if p.GSM != 0 {
+		return fmt.Sprintf("%g gsm", p.GSM)
+	}
+
+	return fmt.Sprintf("%g mm", p.Caliper)
+}
+
+// Cardstock lists common paper and cardstock weights with typical calipers.
+var Cardstock = []PaperWeight{
+	{Name: "copy paper", GSM: 80, Caliper: 0.10},
+	{Name: "65 lb cover", GSM: 176, Caliper: 0.23},
+	{Name: "80 lb cover", GSM: 216, Caliper: 0.27},
+	{Name: "100 lb cover", GSM: 270, Caliper: 0.32},
+	{Name: "110 lb cover", GSM: 298, Caliper: 0.36},
+	{Name: "120 lb cover", GSM: 325, Caliper: 0.41},
+}
+
+const (
+	paperDensity = 800    // typical cardstock density in kg/m³, used to estimate caliper from weight
+	mmPerPoint   = 0.0254 // a caliper point is a thousandth of an inch
+	caliperScale = 10000  // calipers are kept to a tenth of a micrometre
+)
+
+// basisWeights maps US basis weight grades to grams per square metre per pound.
+var basisWeights = map[string]float64{
+	"cover": 2.7084,
+	"text":  1.4805,
+	"bond":  3.7597,
+}
+
+// ParsePaperWeight parses a paper weight or thickness: a weight such as
+// "216gsm", "80lb" or "24 lb bond", a caliper such as "0.3mm" or "12pt", or a
+// name from Cardstock. Pound weights are cover weights unless a grade is
+// given. Caliper is estimated from weight for a typical cardstock density.
+func ParsePaperWeight(s string) (PaperWeight, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+
+	for _, p := range Cardstock {
+		if text == p.Name || strings.ReplaceAll(text, " ", "") == strings.ReplaceAll(p.Name, " ", "") {
+			return p, nil
+		}
+	}
+
+	i := strings.IndexFunc(text, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	if i <= 0 {
+		return PaperWeight{}, fmt.Errorf("%w: paper %q: expected a weight such as 216gsm or 80lb, or a caliper such as 0.3mm", ErrSyntax, s)
+	}
+
+	v, err := ParseDecimal(text[:i])
+	if err != nil {
+		return PaperWeight{}, fmt.Errorf("%w: paper %q: not a number", ErrSyntax, s)
+	}
+
+	value := v.Float64()
+	if value <= 0 {
+		return PaperWeight{}, fmt.Errorf("%w: paper %q must be heavier than zero", ErrInvalidDimension, s)
+	}
+
+	unit := strings.Fields(text[i:])
+
+	switch {
+	case len(unit) == 1 && unit[0] == "gsm", len(unit) == 1 && unit[0] == "g":
+		return weightOf(value), nil
+	case len(unit) == 1 && unit[0] == "mm":
+		return PaperWeight{Caliper: roundCaliper(value)}, nil
+	case len(unit) == 1 && unit[0] == "pt":
+		return PaperWeight{Caliper: roundCaliper(value * mmPerPoint)}, nil
+	case len(unit) >= 1 && unit[0] == "lb":
+		grade := "cover"
+		if len(unit) == 2 {
+			grade = unit[1]
+		}
+
+		gsm, ok := basisWeights[grade]
+		if !ok || len(unit) > 2 {
+			return PaperWeight{}, fmt.Errorf("%w: paper %q: unknown grade, expected cover, text or bond", ErrSyntax, s)
+		}
+
+		return weightOf(value * gsm), nil
+	default:
+		return PaperWeight{}, fmt.Errorf("%w: paper %q: unknown unit, expected gsm, lb, mm or pt", ErrSyntax, s)
+	}
+}
+
+// gsmTolerance is how close a weight must be to a Cardstock weight to take its caliper.
+const gsmTolerance = 1
+
+// weightOf returns the paper of the given weight: the Cardstock entry of that
+// weight if there is one, otherwise paper with an estimated caliper.
+func weightOf(gsm float64) PaperWeight {
+	for _, p := range Cardstock {
+		if math.Abs(p.GSM-gsm) <= gsmTolerance {
+			return p
+		}
+	}
+
+	return PaperWeight{GSM: gsm, Caliper: estimateCaliper(gsm)}
+}
+
+// estimateCaliper returns the caliper of paper of the given weight.
+func estimateCaliper(gsm float64) float64 {
+	return roundCaliper(gsm / paperDensity)
+}
+
+// roundCaliper rounds a caliper to the nearest tenth of a micrometre, finer
+// than it can be measured, so that it has a short exact decimal form.
+func roundCaliper(mm float64) float64 {
+	return math.Round(mm*caliperScale) / caliperScale
+}
+
+// FoldAllowance returns the paper taken up by one fold in paper of the given
+// caliper, in millimetres, measured in unit. Each fold is allowed one paper thickness.
+func FoldAllowance(caliper float64, unit Unit) float64 {
+	return convertFloat(caliper, Millimetre, unit.Length())
+}
@@ -0,0 +1,83 @@
+package calculate
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePaperWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    PaperWeight
+		wantErr error
+	}{
+		{name: "table name", input: "110 lb cover", want: Cardstock[4]},
+		{name: "table name without spaces", input: "110LBCOVER", want: Cardstock[4]},
+		{name: "gsm in the table", input: "216gsm", want: Cardstock[2]},
+		{name: "gsm with space", input: "216 gsm", want: Cardstock[2]},
+		{name: "grams", input: "400g", want: PaperWeight{GSM: 400, Caliper: 0.5}},
+		{name: "cover pounds", input: "80lb", want: Cardstock[2]},
+		{name: "text pounds", input: "100 lb text", want: PaperWeight{GSM: 148.05, Caliper: 0.1851}},
+		{name: "caliper", input: "0.3mm", want: PaperWeight{Caliper: 0.3}},
+		{name: "points", input: "12pt", want: PaperWeight{Caliper: 0.3048}},
+		{name: "no unit", input: "216", wantErr: ErrSyntax},
+		{name: "no number", input: "gsm", wantErr: ErrSyntax},
+		{name: "unknown unit", input: "3oz", wantErr: ErrSyntax},
+		{name: "unknown grade", input: "80 lb index", wantErr: ErrSyntax},
+		{name: "zero", input: "0gsm", wantErr: ErrInvalidDimension},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePaperWeight(tt.input)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Name, got.Name)
+			assert.InDelta(t, tt.want.GSM, got.GSM, 1e-9)
+			assert.InDelta(t, tt.want.Caliper, got.Caliper, 1e-9)
+		})
+	}
+}
+
+func TestFoldAllowance(t *testing.T) {
+	assert.InDelta(t, 0.03, FoldAllowance(0.3, UnitMetric), 1e-12)
+	assert.InDelta(t, 0.3/25.4, FoldAllowance(0.3, UnitImperial), 1e-12)
+}
+
+func TestEnvelopeSpec_CalculateCaliper(t *testing.T) {
+	spec := EnvelopeSpec{Length: 7, Width: 5, Unit: UnitImperial}
+
+	plain, err := spec.Calculate()
+	require.NoError(t, err)
+
+	spec.Caliper = 0.3048
+	env, err := spec.Calculate()
+	require.NoError(t, err)
+
+	f := 0.012
+	assert.InDelta(t, f, env.FoldAllowance, 1e-12)
+	assert.InDelta(t, math.Sqrt2*f, env.FoldCompensation, 1e-12)
+	assert.InDelta(t, plain.Margin+math.Sqrt2*f, env.Margin, 1e-12)
+	assert.InDelta(t, plain.PaperSize+2*math.Sqrt2*f, env.PaperSize, 1e-12)
+	assert.InDelta(t, plain.PunchLocation+math.Sqrt2*f, env.PunchLocation, 1e-12)
+
+	// the floating point projections are rounded, so the paths agree to 1e-9
+	exact, err := spec.CalculateExact()
+	require.NoError(t, err)
+	assert.InDelta(t, env.PaperSize, exact.Envelope().PaperSize, 1e-9)
+	assert.InDelta(t, env.PunchLocation, exact.Envelope().PunchLocation, 1e-9)
+	assert.InDelta(t, env.Margin, exact.Envelope().Margin, 1e-12)
+
+	spec.Caliper = -1
+	_, err = spec.Calculate()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+}
@@ -0,0 +1,319 @@
+package calculate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrSyntax indicates that a value does not have the right syntax.
+	ErrSyntax = errors.New("invalid syntax")
+
+	// ErrRange indicates that a value is out of range.
+	ErrRange = errors.New("value out of range")
+)
+
+// ParseError describes why a decimal string could not be parsed.
+type ParseError struct {
+	Input  string // the string being parsed
+	Pos    int    // byte offset in Input where the problem was found
+	Reason string // what is wrong at Pos
+	Err    error  // ErrSyntax or ErrRange
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("ParseDecimal: parsing %q: %s at position %d", e.Input, e.Reason, e.Pos)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// DecimalFormat holds the separators used to write a decimal number.
+type DecimalFormat struct {
+	Point rune // decimal separator
+	Group rune // thousands separator, or 0 if digits are not grouped
+}
+
+// DecimalPoint is the format used in English: 1,234.5. A decimal written with
+// a comma, as 1.234,5, is read with DecimalFormat{Point: ',', Group: '.'}.
+var DecimalPoint = DecimalFormat{Point: '.', Group: ','}
+
+// ParseDecimal parses a decimal string written with DecimalPoint into a rational number.
+func ParseDecimal(s string) (Rational, error) {
+	return DecimalPoint.Parse(s)
+}
+
+// ParseBigDecimal parses a decimal string written with DecimalPoint into an
+// arbitrary-precision rational number.
+func ParseBigDecimal(s string) (BigRational, error) {
+	return DecimalPoint.ParseBig(s)
+}
+
+// maxDecimalDigits is the number of decimal digits that always fit in an int64.
+const maxDecimalDigits = 18
+
+// groupSize is the number of digits between thousands separators.
+const groupSize = 3
+
+// Parse parses a decimal string into an exact rational number. The grammar is
+//
+//	decimal  = [sign] mantissa [exponent]
+//	sign     = "+" | "-"
+//	mantissa = integer [point [digits]] | point digits
+//	integer  = digits | digit{1,3} (group digit{3})+
+//	exponent = ("e" | "E") [sign] digits
+//
+// where point and group are the separators of f. Errors are returned as a *ParseError.
+func (f DecimalFormat) Parse(s string) (Rational, error) {
+	d, err := f.scan(s)
+	if err != nil {
+		return Rational{}, err
+	}
+
+	return d.rational()
+}
+
+// ParseBig parses a decimal string like Parse, without limiting the number of digits.
+func (f DecimalFormat) ParseBig(s string) (BigRational, error) {
+	d, err := f.scan(s)
+	if err != nil {
+		return BigRational{}, err
+	}
+
+	return d.big()
+}
+
+// decimal is a scanned decimal string with the value ±digits × 10^-scale.
+type decimal struct {
+	input  string
+	neg    bool
+	digits []byte
+	scale  int
+}
+
+func (f DecimalFormat) scan(s string) (decimal, error) {
+	fail := func(pos int, err error, reason string) (decimal, error) {
+		return decimal{}, &ParseError{Input: s, Pos: pos, Reason: reason, Err: err}
+	}
+
+	if s == "" {
+		return fail(0, ErrSyntax, "empty string")
+	}
+
+	pos := 0
+
+	neg := s[0] == '-'
+	if s[0] == '+' || s[0] == '-' {
+		pos++
+	}
+
+	var (
+		digits     []byte // mantissa digits without separators
+		fracDigits int    // digits after the decimal separator
+		group      = -1   // digits since the last thousands separator, or -1 if there is none
+		groupPos   int    // position of the last thousands separator
+		point      bool   // the decimal separator has been read
+	)
+
+	// endGroups checks the last digit group when the integer part ends
+	endGroups := func() bool {
+		return group < 0 || group == groupSize
+	}
+
+mantissa:
+	for pos < len(s) {
+		r, size := utf8.DecodeRuneInString(s[pos:])
+
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, byte(r))
+
+			switch {
+			case point:
+				fracDigits++
+			case group >= 0:
+				group++
+			}
+		case r == f.Point:
+			if point {
+				return fail(pos, ErrSyntax, "second decimal separator")
+			}
+
+			if !endGroups() {
+				return fail(groupPos, ErrSyntax, "digit group must have three digits")
+			}
+
+			point = true
+		case r == f.Group && f.Group != 0:
+			switch {
+			case point:
+				return fail(pos, ErrSyntax, "thousands separator after decimal separator")
+			case len(digits) == 0:
+				return fail(pos, ErrSyntax, "thousands separator before first digit")
+			case group < 0 && len(digits) > groupSize:
+				return fail(pos, ErrSyntax, "first digit group has more than three digits")
+			case !endGroups():
+				return fail(groupPos, ErrSyntax, "digit group must have three digits")
+			}
+
+			group, groupPos = 0, pos
+		case r == 'e' || r == 'E':
+			break mantissa
+		case r == utf8.RuneError && size == 1:
+			return fail(pos, ErrSyntax, "invalid UTF-8")
+		default:
+			return fail(pos, ErrSyntax, fmt.Sprintf("unexpected %q", r))
+		}
+
+		pos += size
+	}
+
+	if len(digits) == 0 {
+		return fail(pos, ErrSyntax, "no digits")
+	}
+
+	if !point && !endGroups() {
+		return fail(groupPos, ErrSyntax, "digit group must have three digits")
+	}
+
+	exp, err := f.parseExponent(s, pos)
+	if err != nil {
+		return decimal{}, err
+	}
+
+	return decimal{input: s, neg: neg, digits: digits, scale: fracDigits - exp}, nil
+}
+
+// parseExponent parses the optional exponent starting at pos.
+func (f DecimalFormat) parseExponent(s string, pos int) (int, error) {
+	// exponents beyond this are out of range for any mantissa
+	const maxExponent = 1 << 20
+
+	if pos == len(s) {
+		return 0, nil
+	}
+
+	pos++ // 'e' or 'E'
+
+	neg := pos < len(s) && s[pos] == '-'
+	if pos < len(s) && (s[pos] == '+' || s[pos] == '-') {
+		pos++
+	}
+
+	if pos == len(s) {
+		return 0, &ParseError{Input: s, Pos: pos, Reason: "exponent has no digits", Err: ErrSyntax}
+	}
+
+	exp := 0
+
+	for ; pos < len(s); pos++ {
+		c := s[pos]
+		if c < '0' || c > '9' {
+			r, _ := utf8.DecodeRuneInString(s[pos:])
+			return 0, &ParseError{Input: s, Pos: pos, Reason: fmt.Sprintf("unexpected %q in exponent", r), Err: ErrSyntax}
+		}
+
+		if exp < maxExponent {
+			exp = exp*10 + int(c-'0')
+		}
+	}
+
+	if neg {
+		return -exp, nil
+	}
+
+	return exp, nil
+}
+
+// trim removes leading and trailing zeros from the digits, adjusting the scale.
+func (d decimal) trim() decimal {
+	d.digits = []byte(strings.TrimLeft(string(d.digits), "0"))
+
+	// trailing zeros only scale the value
+	for len(d.digits) > 0 && d.digits[len(d.digits)-1] == '0' {
+		d.digits = d.digits[:len(d.digits)-1]
+		d.scale--
+	}
+
+	return d
+}
+
+func (d decimal) outOfRange() error {
+	return &ParseError{Input: d.input, Pos: 0, Reason: "value out of range", Err: ErrRange}
+}
+
+// rational returns the value as a Rational.
+func (d decimal) rational() (Rational, error) {
+	d = d.trim()
+	if len(d.digits) == 0 {
+		return Rational{}, nil
+	}
+
+	if d.scale < 0 {
+		d.digits = append(d.digits, strings.Repeat("0", min(-d.scale, maxDecimalDigits+1))...)
+		d.scale = 0
+	}
+
+	if len(d.digits) > maxDecimalDigits || d.scale > maxDecimalDigits {
+		return Rational{}, d.outOfRange()
+	}
+
+	var p int64
+	for _, c := range d.digits {
+		p = p*10 + int64(c-'0')
+	}
+
+	if d.neg {
+		p = -p
+	}
+
+	return newFraction(p, int64(math.Pow10(d.scale))), nil
+}
+
+// maxBigScale limits the size of a BigRational read from a decimal string.
+const maxBigScale = 10000
+
+// big returns the value as a BigRational.
+func (d decimal) big() (BigRational, error) {
+	d = d.trim()
+	if len(d.digits) == 0 {
+		return BigRational{}, nil
+	}
+
+	if d.scale > maxBigScale || d.scale < -maxBigScale {
+		return BigRational{}, d.outOfRange()
+	}
+
+	p, _ := new(big.Int).SetString(string(d.digits), 10)
+	if d.neg {
+		p.Neg(p)
+	}
+
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(d.scale))), nil)
+	if d.scale < 0 {
+		return BigRational{new(big.Rat).SetInt(p.Mul(p, pow))}, nil
+	}
+
+	return BigRational{new(big.Rat).SetFrac(p, pow)}, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+
+	return a
+}
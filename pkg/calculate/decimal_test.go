@@ -0,0 +1,295 @@
+package calculate
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		wantR     Rational
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "empty string",
+			arg:       "",
+			wantR:     Rational{},
+			assertion: assert.Error,
+		},
+		{
+			name: "integer",
+			arg:  "1",
+			wantR: Rational{
+				i: 1,
+				n: 0,
+				d: 0,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "zero",
+			arg:  "0",
+			wantR: Rational{
+				i: 0,
+				n: 0,
+				d: 0,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "neg zero",
+			arg:  "-0",
+			wantR: Rational{
+				i: 0,
+				n: 0,
+				d: 0,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "int - no fraction",
+			arg:  "1.0",
+			wantR: Rational{
+				i: 1,
+				n: 0,
+				d: 0,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "int - fraction",
+			arg:  "1.1",
+			wantR: Rational{
+				i: 1,
+				n: 1,
+				d: 10,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "int - simplify fraction",
+			arg:  "1.2",
+			wantR: Rational{
+				i: 1,
+				n: 1,
+				d: 5,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "neg int - simplify fraction",
+			arg:  "-1.2",
+			wantR: Rational{
+				i: -1,
+				n: 1,
+				d: 5,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "neg integer",
+			arg:  "-3",
+			wantR: Rational{
+				i: -3,
+				n: 0,
+				d: 0,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "neg - no leading int",
+			arg:  "-.2",
+			wantR: Rational{
+				i: 0,
+				n: -1,
+				d: 5,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "exponent",
+			arg:       "1e-2",
+			wantR:     Rational{n: 1, d: 100},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "exponent with fraction",
+			arg:       "+1.25E2",
+			wantR:     Rational{i: 125},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "thousands separators",
+			arg:       "-1,234.5",
+			wantR:     Rational{i: -1234, n: 1, d: 2},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "trailing point",
+			arg:       "3.",
+			wantR:     Rational{i: 3},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "trailing zeros",
+			arg:       "0.50000000000000000000000",
+			wantR:     Rational{n: 1, d: 2},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "decimal comma",
+			arg:       "1,5",
+			assertion: assert.Error,
+		},
+		{
+			name:      "sign only",
+			arg:       "-",
+			assertion: assert.Error,
+		},
+		{
+			name:      "too many digits",
+			arg:       "1.0000000000000000001",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotR, err := ParseDecimal(tt.arg)
+
+			tt.assertion(t, err)
+
+			if err == nil {
+				assert.Equal(t, tt.wantR, gotR)
+			}
+		})
+	}
+}
+
+func TestParseDecimal_Error(t *testing.T) {
+	tests := []struct {
+		name       string
+		arg        string
+		wantPos    int
+		wantReason string
+		wantErr    error
+	}{
+		{name: "empty", arg: "", wantPos: 0, wantReason: "empty string", wantErr: ErrSyntax},
+		{name: "letter", arg: "12a", wantPos: 2, wantReason: `unexpected 'a'`, wantErr: ErrSyntax},
+		{name: "second point", arg: "1.2.3", wantPos: 3, wantReason: "second decimal separator", wantErr: ErrSyntax},
+		{name: "short group", arg: "1,23", wantPos: 1, wantReason: "digit group must have three digits", wantErr: ErrSyntax},
+		{name: "short group before point", arg: "1,2345.6", wantPos: 1, wantReason: "digit group must have three digits", wantErr: ErrSyntax},
+		{name: "long first group", arg: "1234,567", wantPos: 4, wantReason: "first digit group has more than three digits", wantErr: ErrSyntax},
+		{name: "leading group", arg: ",123", wantPos: 0, wantReason: "thousands separator before first digit", wantErr: ErrSyntax},
+		{name: "group in fraction", arg: "0.123,4", wantPos: 5, wantReason: "thousands separator after decimal separator", wantErr: ErrSyntax},
+		{name: "no digits", arg: "+.", wantPos: 2, wantReason: "no digits", wantErr: ErrSyntax},
+		{name: "empty exponent", arg: "1e+", wantPos: 3, wantReason: "exponent has no digits", wantErr: ErrSyntax},
+		{name: "bad exponent", arg: "1e2.5", wantPos: 3, wantReason: `unexpected '.' in exponent`, wantErr: ErrSyntax},
+		{name: "too large", arg: "1e19", wantPos: 0, wantReason: "value out of range", wantErr: ErrRange},
+		{name: "too small", arg: "1e-19", wantPos: 0, wantReason: "value out of range", wantErr: ErrRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDecimal(tt.arg)
+
+			var perr *ParseError
+			if assert.True(t, errors.As(err, &perr)) {
+				assert.Equal(t, tt.arg, perr.Input)
+				assert.Equal(t, tt.wantPos, perr.Pos)
+				assert.Equal(t, tt.wantReason, perr.Reason)
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), strconv.Quote(tt.arg))
+		})
+	}
+}
+
+func TestDecimalFormat_Parse(t *testing.T) {
+	comma := DecimalFormat{Point: ',', Group: '.'}
+
+	got, err := comma.Parse("1,5")
+	assert.NoError(t, err)
+	assert.Equal(t, Rational{i: 1, n: 1, d: 2}, got)
+
+	got, err = comma.Parse("-1.234,25")
+	assert.NoError(t, err)
+	assert.Equal(t, Rational{i: -1234, n: 1, d: 4}, got)
+
+	_, err = comma.Parse("1.5")
+	assert.ErrorIs(t, err, ErrSyntax)
+
+	got, err = DecimalFormat{Point: '.'}.Parse("1234.5")
+	assert.NoError(t, err)
+	assert.Equal(t, Rational{i: 1234, n: 1, d: 2}, got)
+
+	_, err = DecimalFormat{Point: '.'}.Parse("1,234")
+	assert.ErrorIs(t, err, ErrSyntax)
+}
+
+// floatChars are the only characters in strings that both ParseDecimal and
+// strconv.ParseFloat read the same way.
+const floatChars = "0123456789.eE+-"
+
+func FuzzParseDecimal(f *testing.F) {
+	for _, s := range []string{"1", "-1.2", "-.2", "1e-2", "1,234.5", "3.", "0.4375", "1e19", "+.", "9.36"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		r, err := ParseDecimal(s)
+		if err != nil {
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("ParseDecimal(%q) returned %T, want *ParseError", s, err)
+			}
+
+			if perr.Pos < 0 || perr.Pos > len(s) {
+				t.Fatalf("ParseDecimal(%q) error position %d out of bounds", s, perr.Pos)
+			}
+		}
+
+		want, ferr := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+
+		if err == nil {
+			if ferr != nil {
+				t.Fatalf("ParseDecimal(%q) = %v, but strconv.ParseFloat failed: %v", s, r, ferr)
+			}
+
+			assertClose(t, s, want, r.Float64())
+		}
+
+		if ferr != nil || strings.Trim(s, floatChars) != "" || math.IsInf(want, 0) {
+			return
+		}
+
+		if err != nil && !errors.Is(err, ErrRange) {
+			t.Fatalf("strconv.ParseFloat(%q) = %v, but ParseDecimal failed: %v", s, want, err)
+		}
+
+		// the shortest decimal form of a float round-trips through ParseDecimal
+		r, err = ParseDecimal(strconv.FormatFloat(want, 'f', -1, 64))
+		if err == nil {
+			assertClose(t, s, want, r.Float64())
+		} else if !errors.Is(err, ErrRange) {
+			t.Fatalf("ParseDecimal(FormatFloat(%v)) failed: %v", want, err)
+		}
+	})
+}
+
+// assertClose fails unless got is within a few rounding errors of want.
+func assertClose(t *testing.T, s string, want, got float64) {
+	t.Helper()
+
+	const tolerance = 1e-15
+
+	if math.Abs(got-want) > tolerance*math.Abs(want) {
+		t.Fatalf("ParseDecimal(%q).Float64() = %v, want %v", s, got, want)
+	}
+}
@@ -0,0 +1,370 @@
+package calculate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrInvalidDimension is returned when a content dimension cannot be used for a calculation.
+	ErrInvalidDimension = errors.New("invalid dimension")
+
+	// ErrBoardLimit is returned when the paper needed is larger than the board can handle.
+	ErrBoardLimit = errors.New("board limit exceeded")
+)
+
+// Unit is the measurement system used for content dimensions and results.
+type Unit int
+
+// Supported measurement units.
+const (
+	UnitMetric   Unit = iota // centimeters
+	UnitImperial             // inches
+)
+
+func (u Unit) String() string {
+	switch u {
+	case UnitMetric:
+		return "cm"
+	case UnitImperial:
+		return "in"
+	default:
+		return fmt.Sprintf("Unit(%d)", int(u))
+	}
+}
+
+// ParseUnit parses a unit name such as "cm" or "in".
+func ParseUnit(s string) (Unit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "cm", "metric":
+		return UnitMetric, nil
+	case "in", "inch", "inches", "imperial":
+		return UnitImperial, nil
+	default:
+		return UnitMetric, fmt.Errorf("unknown unit %q", s)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u Unit) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *Unit) UnmarshalText(text []byte) error {
+	v, err := ParseUnit(string(text))
+	if err != nil {
+		return err
+	}
+
+	*u = v
+
+	return nil
+}
+
+// Board is the punch board model used to make an envelope.
+type Board int
+
+// Supported punch boards.
+const (
+	BoardStandard Board = iota // full size 1-2-3 punch board
+	BoardMini                  // mini 1-2-3 punch board
+)
+
+func (b Board) String() string {
+	switch b {
+	case BoardStandard:
+		return "standard"
+	case BoardMini:
+		return "mini"
+	default:
+		return fmt.Sprintf("Board(%d)", int(b))
+	}
+}
+
+// ParseBoard parses a board name such as "standard" or "mini".
+func ParseBoard(s string) (Board, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "standard", "std", "full":
+		return BoardStandard, nil
+	case "mini":
+		return BoardMini, nil
+	default:
+		return BoardStandard, fmt.Errorf("unknown board %q", s)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b Board) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *Board) UnmarshalText(text []byte) error {
+	v, err := ParseBoard(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = v
+
+	return nil
+}
+
+// Orientation is the way content is placed on the paper, named by the content
+// edge that runs left from the top corner. The punch location is measured
+// along that edge's projection.
+type Orientation int
+
+// Supported orientations.
+const (
+	OrientationAuto   Orientation = iota // the shorter edge runs left, as the original calculator drew it
+	OrientationWidth                     // the width edge runs left and the length edge right
+	OrientationLength                    // the length edge runs left and the width edge right
+)
+
+func (o Orientation) String() string {
+	switch o {
+	case OrientationAuto:
+		return "auto"
+	case OrientationWidth:
+		return "width"
+	case OrientationLength:
+		return "length"
+	default:
+		return fmt.Sprintf("Orientation(%d)", int(o))
+	}
+}
+
+// ParseOrientation parses an orientation: "auto", "width" or "length".
+func ParseOrientation(s string) (Orientation, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "auto", "":
+		return OrientationAuto, nil
+	case "width", "w":
+		return OrientationWidth, nil
+	case "length", "l":
+		return OrientationLength, nil
+	default:
+		return OrientationAuto, fmt.Errorf("unknown orientation %q", s)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (o Orientation) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (o *Orientation) UnmarshalText(text []byte) error {
+	v, err := ParseOrientation(string(text))
+	if err != nil {
+		return err
+	}
+
+	*o = v
+
+	return nil
+}
+
+// ParseContent parses how content fits in an envelope. It returns true for
+// "loose" or "thick" content and false for "snug" or "flat" content.
+func ParseContent(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "snug", "flat":
+		return false, nil
+	case "loose", "thick":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown content fit %q", s)
+	}
+}
+
+// MaxPaperSize returns the side of the largest square sheet the board's guide can hold.
+func MaxPaperSize(unit Unit, board Board) float64 {
+	const (
+		maxMetric       float64 = 30.5
+		maxImperial     float64 = 12
+		maxMiniMetric   float64 = 21.6
+		maxMiniImperial float64 = 8.5
+	)
+
+	switch {
+	case board == BoardMini && unit == UnitImperial:
+		return maxMiniImperial
+	case board == BoardMini:
+		return maxMiniMetric
+	case unit == UnitImperial:
+		return maxImperial
+	default:
+		return maxMetric
+	}
+}
+
+// Margin returns the distance between the content corner and the paper edge for a board.
+func Margin(unit Unit, board Board, isLoose bool) float64 {
+	const (
+		marginMetric            float64 = 1.1
+		marginMetricLoose       float64 = 1.5
+		marginImperial          float64 = 0.4375
+		marginImperialLoose     float64 = 0.625
+		marginMiniMetric        float64 = 0.67
+		marginMiniMetricLoose   float64 = 0.914
+		marginMiniImperial      float64 = 0.25
+		marginMiniImperialLoose float64 = 0.34
+	)
+
+	switch {
+	case board == BoardMini && unit == UnitImperial && isLoose:
+		return marginMiniImperialLoose
+	case board == BoardMini && unit == UnitImperial:
+		return marginMiniImperial
+	case board == BoardMini && isLoose:
+		return marginMiniMetricLoose
+	case board == BoardMini:
+		return marginMiniMetric
+	case unit == UnitImperial && isLoose:
+		return marginImperialLoose
+	case unit == UnitImperial:
+		return marginImperial
+	case isLoose:
+		return marginMetricLoose
+	default:
+		return marginMetric
+	}
+}
+
+// EnvelopeSpec describes the content and board settings for an envelope.
+type EnvelopeSpec struct {
+	Length float64 `json:"length"` // content length
+	Width  float64 `json:"width"`  // content width
+	Loose  bool    `json:"loose"`  // leave extra room for thick content
+	Board  Board   `json:"board"`
+	Unit   Unit    `json:"unit"`
+
+	LinerInset float64 `json:"liner_inset,omitempty"` // inset of the liner from the paper edges; zero for no liner
+	Inserts    bool    `json:"inserts,omitempty"`     // size an insert card, layer mat and belly band for the content
+	CheckFlaps bool    `json:"check_flaps,omitempty"` // analyse how the flaps overlap
+
+	Orientation Orientation `json:"orientation,omitempty"` // way to place the content; auto for the shorter edge to the left
+	Steps       bool        `json:"steps,omitempty"`       // give step-by-step board instructions
+	Caliper     float64     `json:"caliper,omitempty"`     // paper thickness in millimetres; zero to ignore it
+
+	// Items are the pieces of content stacked in the envelope. When there are
+	// any, the length and width are those the stack needs.
+	Items []ContentItem `json:"items,omitempty"`
+
+	StockSizes []float64 `json:"stock_sizes,omitempty"` // square paper sizes to round up to, smallest first
+
+	Tolerance *Tolerance `json:"tolerance,omitempty"` // measurement tolerance to report the sensitivity to; nil for none
+}
+
+// Envelope is the result of an envelope calculation.
+type Envelope struct {
+	Spec             EnvelopeSpec  `json:"spec"`
+	Margin           float64       `json:"margin"`                      // distance from content corner to paper edge, including fold compensation
+	LengthProjection float64       `json:"length_projection"`           // content length projected onto the paper edge
+	WidthProjection  float64       `json:"width_projection"`            // content width projected onto the paper edge
+	PaperSize        float64       `json:"paper_size"`                  // side of the square paper
+	PunchLocation    float64       `json:"punch_location"`              // first punch position along the paper edge
+	Orientation      Orientation   `json:"orientation"`                 // content edge the punch location is measured along, width or length
+	FoldAllowance    float64       `json:"fold_allowance,omitempty"`    // paper taken up by each fold
+	FoldCompensation float64       `json:"fold_compensation,omitempty"` // added to the margin for the fold allowance
+	Liner            *Liner        `json:"liner,omitempty"`             // liner, if the spec has a liner inset
+	Inserts          *Inserts      `json:"inserts,omitempty"`           // inserts, if the spec asks for them
+	Flaps            *FlapAnalysis `json:"flaps,omitempty"`             // flap analysis, if the spec asks for it
+	Stack            *Stack        `json:"stack,omitempty"`             // stacked content, if the spec has items
+	Stock            *StockFit     `json:"stock,omitempty"`             // layout on a stock size, if the spec has stock sizes
+	Instructions     *Instructions `json:"instructions,omitempty"`      // board instructions, if the spec asks for them
+	Sensitivity      *Sensitivity  `json:"sensitivity,omitempty"`       // sensitivity report, if the spec has a tolerance
+}
+
+// Validate reports whether the spec can be used for a calculation.
+func (s EnvelopeSpec) Validate() error {
+	if s.Length <= 0 {
+		return fmt.Errorf("%w: length must be greater than zero, got %g", ErrInvalidDimension, s.Length)
+	}
+
+	if s.Width <= 0 {
+		return fmt.Errorf("%w: width must be greater than zero, got %g", ErrInvalidDimension, s.Width)
+	}
+
+	if s.Caliper < 0 {
+		return fmt.Errorf("%w: paper caliper must not be negative, got %g", ErrInvalidDimension, s.Caliper)
+	}
+
+	if s.LinerInset < 0 {
+		return fmt.Errorf("%w: liner inset must not be negative, got %g", ErrInvalidDimension, s.LinerInset)
+	}
+
+	if s.Tolerance != nil {
+		return s.Tolerance.Validate()
+	}
+
+	return nil
+}
+
+// orientation returns the orientation to place the content in, choosing the
+// shorter edge, or the width for square content, when the spec leaves it to auto.
+func (s EnvelopeSpec) orientation() Orientation {
+	if s.Orientation != OrientationAuto {
+		return s.Orientation
+	}
+
+	if s.Width > s.Length {
+		return OrientationLength
+	}
+
+	return OrientationWidth
+}
+
+// Sides returns the projections onto the top paper edge of the content edges
+// running left and right from the top corner of the content. An envelope
+// without an orientation has the shorter edge to the left.
+func (e Envelope) Sides() (left, right float64) {
+	switch e.Orientation {
+	case OrientationWidth:
+		return e.WidthProjection, e.LengthProjection
+	case OrientationLength:
+		return e.LengthProjection, e.WidthProjection
+	default:
+		return math.Min(e.LengthProjection, e.WidthProjection), math.Max(e.LengthProjection, e.WidthProjection)
+	}
+}
+
+// withStack returns the spec with the length and width its items need, and
+// their stack, or the spec unchanged if it has no items.
+func (s EnvelopeSpec) withStack() (EnvelopeSpec, *Stack, error) {
+	if len(s.Items) == 0 {
+		return s, nil, nil
+	}
+
+	st, err := NewStack(s.Items)
+	if err != nil {
+		return s, nil, err
+	}
+
+	s.Length, s.Width = st.EnvelopeContent()
+
+	return s, &st, nil
+}
+
+// margin returns the margin for the spec's board and content, increased by the
+// fold compensation, and the fold allowance it was calculated from.
+//
+// Each content edge is folded around, so the folds sit an allowance further out
+// from the content on every side and each projection grows by √2 allowances.
+func (s EnvelopeSpec) margin() (margin, allowance, compensation float64) {
+	allowance = FoldAllowance(s.Caliper, s.Unit)
+	compensation = math.Sqrt2 * allowance
+
+	return Margin(s.Unit, s.Board, s.Loose) + compensation, allowance, compensation
+}
+
+// boardLimitError returns the error for a paper size larger than the board can handle.
+func (s EnvelopeSpec) boardLimitError(paper float64) error {
+	return fmt.Errorf("%w: paper size %0.2f %s is larger than the %s board limit of %g %s",
+		ErrBoardLimit, paper, s.Unit, s.Board, MaxPaperSize(s.Unit, s.Board), s.Unit)
+}
@@ -0,0 +1,194 @@
+package calculate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelopeSpec_Calculate(t *testing.T) {
+	tests := []struct {
+		name              string
+		spec              EnvelopeSpec
+		wantPaperSize     float64
+		wantPunchLocation float64
+		wantErr           error
+	}{
+		{
+			name:              "10x8 cm - loose",
+			spec:              EnvelopeSpec{Length: 10, Width: 8, Loose: true, Board: BoardStandard, Unit: UnitMetric},
+			wantPaperSize:     15.73,
+			wantPunchLocation: 7.16,
+		},
+		{
+			name:              "5x7 in",
+			spec:              EnvelopeSpec{Length: 5, Width: 7, Board: BoardStandard, Unit: UnitImperial},
+			wantPaperSize:     9.36,
+			wantPunchLocation: 3.97,
+		},
+		{
+			name:              "3x2 in - mini",
+			spec:              EnvelopeSpec{Length: 3, Width: 2, Board: BoardMini, Unit: UnitImperial},
+			wantPaperSize:     4.04,
+			wantPunchLocation: 1.66,
+		},
+		{
+			name:    "zero length",
+			spec:    EnvelopeSpec{Length: 0, Width: 8},
+			wantErr: ErrInvalidDimension,
+		},
+		{
+			name:    "negative width",
+			spec:    EnvelopeSpec{Length: 10, Width: -8},
+			wantErr: ErrInvalidDimension,
+		},
+		{
+			name:    "too large for board",
+			spec:    EnvelopeSpec{Length: 25, Width: 20, Board: BoardStandard, Unit: UnitMetric},
+			wantErr: ErrBoardLimit,
+		},
+		{
+			name:    "too large for mini board",
+			spec:    EnvelopeSpec{Length: 7, Width: 5, Board: BoardMini, Unit: UnitImperial},
+			wantErr: ErrBoardLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Calculate()
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.spec, got.Spec)
+			assert.InDelta(t, tt.wantPaperSize, got.PaperSize, 0.01)
+			assert.InDelta(t, tt.wantPunchLocation, got.PunchLocation, 0.01)
+		})
+	}
+}
+
+func TestMargin(t *testing.T) {
+	type args struct {
+		unit    Unit
+		board   Board
+		isLoose bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{name: "standard metric", args: args{UnitMetric, BoardStandard, false}, want: 1.1},
+		{name: "standard metric loose", args: args{UnitMetric, BoardStandard, true}, want: 1.5},
+		{name: "standard imperial", args: args{UnitImperial, BoardStandard, false}, want: 0.4375},
+		{name: "standard imperial loose", args: args{UnitImperial, BoardStandard, true}, want: 0.625},
+		{name: "mini metric", args: args{UnitMetric, BoardMini, false}, want: 0.67},
+		{name: "mini metric loose", args: args{UnitMetric, BoardMini, true}, want: 0.914},
+		{name: "mini imperial", args: args{UnitImperial, BoardMini, false}, want: 0.25},
+		{name: "mini imperial loose", args: args{UnitImperial, BoardMini, true}, want: 0.34},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Margin(tt.args.unit, tt.args.board, tt.args.isLoose))
+		})
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      Unit
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "cm", arg: "cm", want: UnitMetric, assertion: assert.NoError},
+		{name: "metric", arg: "Metric", want: UnitMetric, assertion: assert.NoError},
+		{name: "in", arg: "in", want: UnitImperial, assertion: assert.NoError},
+		{name: "inch", arg: " inch ", want: UnitImperial, assertion: assert.NoError},
+		{name: "unknown", arg: "furlong", want: UnitMetric, assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUnit(tt.arg)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseContent(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      bool
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "snug", arg: "snug", want: false, assertion: assert.NoError},
+		{name: "flat", arg: "Flat", want: false, assertion: assert.NoError},
+		{name: "loose", arg: "loose", want: true, assertion: assert.NoError},
+		{name: "thick", arg: " THICK", want: true, assertion: assert.NoError},
+		{name: "unknown", arg: "bulky", want: false, assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseContent(tt.arg)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMaxPaperSize(t *testing.T) {
+	assert.Equal(t, 30.5, MaxPaperSize(UnitMetric, BoardStandard))
+	assert.Equal(t, 12.0, MaxPaperSize(UnitImperial, BoardStandard))
+	assert.Equal(t, 21.6, MaxPaperSize(UnitMetric, BoardMini))
+	assert.Equal(t, 8.5, MaxPaperSize(UnitImperial, BoardMini))
+}
+
+func TestUnit_Text(t *testing.T) {
+	got, err := UnitImperial.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "in", string(got))
+
+	var u Unit
+	assert.NoError(t, u.UnmarshalText([]byte("cm")))
+	assert.Equal(t, UnitMetric, u)
+	assert.Error(t, u.UnmarshalText([]byte("furlong")))
+}
+
+func TestBoard_Text(t *testing.T) {
+	got, err := BoardMini.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "mini", string(got))
+
+	var b Board
+	assert.NoError(t, b.UnmarshalText([]byte("mini")))
+	assert.Equal(t, BoardMini, b)
+	assert.Error(t, b.UnmarshalText([]byte("maxi")))
+}
+
+func TestParseBoard(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      Board
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "standard", arg: "standard", want: BoardStandard, assertion: assert.NoError},
+		{name: "mini", arg: "MINI", want: BoardMini, assertion: assert.NoError},
+		{name: "unknown", arg: "maxi", want: BoardStandard, assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBoard(tt.arg)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Exact is a number of the form A + B·√½, where A and B are rational. Every
+// length in an envelope layout has this form, so it can be computed, compared
+// and rounded without any floating point error. The parts are BigRationals, so
+// long decimal inputs never overflow.
+type Exact struct {
+	A BigRational // rational part
+	B BigRational // coefficient of √½
+}
+
+func (x Exact) String() string {
+	return x.A.String() + " + (" + x.B.String() + ")√½"
+}
+
+// Add returns x + y.
+func (x Exact) Add(y Exact) Exact {
+	return Exact{A: x.A.Add(y.A), B: x.B.Add(y.B)}
+}
+
+// Float64 returns the float64 value of x. Each of A, B and √½ is correctly
+// rounded, so the result is within MaxFloatError of the exact value.
+func (x Exact) Float64() float64 {
+	return x.A.Float64() + x.B.Float64()*math.Sqrt(0.5)
+}
+
+// MaxFloatError bounds the relative difference between the result of Float64
+// and the exact value, for values whose parts are not negative. Rounding A, B,
+// √½, the product and the sum each add at most 2⁻⁵³.
+const MaxFloatError = 0x1p-50
+
+// CmpRational returns -1, 0 or +1 depending on whether x is less than, equal to
+// or greater than r. The comparison is exact.
+func (x Exact) CmpRational(r BigRational) int {
+	// sign of u + v·√½
+	u := x.A.Sub(r)
+	v := x.B
+
+	su, sv := u.Sign(), v.Sign()
+
+	switch {
+	case su >= 0 && sv >= 0:
+		if su == 0 && sv == 0 {
+			return 0
+		}
+
+		return 1
+	case su <= 0 && sv <= 0:
+		return -1
+	}
+
+	// the parts have opposite signs: compare u² with v²/2
+	d := u.Mul(u).Sub(v.Mul(v).Mul(bigFraction(1, 2))).Sign()
+	if su < 0 {
+		return -d
+	}
+
+	return d
+}
+
+// Floor returns the largest multiple of 1/denom that is not greater than x.
+func (x Exact) Floor(denom int64) BigRational {
+	// start from an estimate and correct it exactly
+	k := x.scaledFloor(denom)
+	one := big.NewInt(1)
+
+	multiple := func(k *big.Int) BigRational {
+		return BigRational{new(big.Rat).SetFrac(k, big.NewInt(denom))}
+	}
+
+	for x.CmpRational(multiple(k)) < 0 {
+		k.Sub(k, one)
+	}
+
+	for x.CmpRational(multiple(new(big.Int).Add(k, one))) >= 0 {
+		k.Add(k, one)
+	}
+
+	return multiple(k)
+}
+
+// scaledFloor estimates the floor of x·denom. It is computed with enough bits
+// to hold the parts of x, so it is at most one away from the exact result.
+func (x Exact) scaledFloor(denom int64) *big.Int {
+	a, b := x.A.rat(), x.B.rat()
+	prec := uint(a.Num().BitLen()+a.Denom().BitLen()+b.Num().BitLen()+b.Denom().BitLen()) + 128
+
+	root := new(big.Float).SetPrec(prec).Sqrt(big.NewFloat(0.5))
+	f := new(big.Float).SetPrec(prec).SetRat(b)
+	f.Mul(f, root)
+	f.Add(f, new(big.Float).SetPrec(prec).SetRat(a))
+	f.Mul(f, new(big.Float).SetInt64(denom))
+
+	k, _ := f.Int(nil)
+
+	return k
+}
+
+// Round returns the multiple of 1/denom nearest to x, rounding halves up.
+func (x Exact) Round(denom int64) BigRational {
+	return Exact{A: x.A.Add(bigFraction(1, 2*denom)), B: x.B}.Floor(denom)
+}
+
+// RoundDecimal returns x rounded to the given number of decimal places, rounding halves up.
+func (x Exact) RoundDecimal(places int) BigRational {
+	return x.Round(int64(math.Pow10(places)))
+}
+
+// Format returns x rounded for display: to the nearest 1/fraction as a mixed
+// number when fraction is positive, otherwise to precision decimal places.
+func (x Exact) Format(precision int, fraction int64) string {
+	if fraction > 0 {
+		return x.Round(fraction).Mixed()
+	}
+
+	// a multiple of 10^-precision prints exactly at that precision
+	return x.RoundDecimal(precision).rat().FloatString(precision)
+}
+
+// RationalFromFloat returns the Rational with the shortest decimal representation of f,
+// which is the value a user typed to produce f.
+func RationalFromFloat(f float64) (Rational, error) {
+	return ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// BigRationalFromFloat returns the BigRational with the shortest decimal
+// representation of f. Unlike RationalFromFloat it holds every float64.
+func BigRationalFromFloat(f float64) (BigRational, error) {
+	return ParseBigDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// bigFraction returns the BigRational p/q.
+func bigFraction(p, q int64) BigRational {
+	return BigRational{big.NewRat(p, q)}
+}
+
+// ExactEnvelope is an envelope calculation carried out without rounding.
+type ExactEnvelope struct {
+	Spec          EnvelopeSpec
+	Stack         *Stack
+	Length        BigRational
+	Width         BigRational
+	Margin        BigRational
+	FoldAllowance BigRational // paper taken up by each fold; the margin is increased by √2 of these
+	PaperSize     Exact
+	PunchLocation Exact
+}
+
+// CalculateExact calculates the paper size and punch location for an envelope
+// using exact arithmetic. The dimensions and margin are converted to BigRational
+// from their shortest decimal representation, and √½ is kept as a symbolic factor.
+func (s EnvelopeSpec) CalculateExact() (ExactEnvelope, error) {
+	s, stack, err := s.withStack()
+	if err != nil {
+		return ExactEnvelope{}, err
+	}
+
+	if err := s.Validate(); err != nil {
+		return ExactEnvelope{}, err
+	}
+
+	length, err := BigRationalFromFloat(s.Length)
+	if err != nil {
+		return ExactEnvelope{}, fmt.Errorf("%w: length: %v", ErrInvalidDimension, err)
+	}
+
+	width, err := BigRationalFromFloat(s.Width)
+	if err != nil {
+		return ExactEnvelope{}, fmt.Errorf("%w: width: %v", ErrInvalidDimension, err)
+	}
+
+	margin, err := BigRationalFromFloat(Margin(s.Unit, s.Board, s.Loose))
+	if err != nil {
+		return ExactEnvelope{}, err
+	}
+
+	caliper, err := BigRationalFromFloat(s.Caliper)
+	if err != nil {
+		return ExactEnvelope{}, fmt.Errorf("%w: caliper: %v", ErrInvalidDimension, err)
+	}
+
+	allowance := Measurement{Value: caliper, Unit: Millimetre}.To(s.Unit.Length()).Value
+
+	// the punch is measured along the edge running left from the top corner
+	left := width
+	if s.orientation() == OrientationLength {
+		left = length
+	}
+
+	two := bigFraction(2, 1)
+	four := bigFraction(4, 1)
+
+	// the compensation √2·allowance is 2·allowance·√½
+	env := ExactEnvelope{
+		Spec:          s,
+		Stack:         stack,
+		Length:        length,
+		Width:         width,
+		Margin:        margin,
+		FoldAllowance: allowance,
+		PaperSize:     Exact{A: margin.Mul(two), B: length.Add(width).Add(allowance.Mul(four))},
+		PunchLocation: Exact{A: margin, B: left.Add(allowance.Mul(two))},
+	}
+
+	if limit, _ := BigRationalFromFloat(MaxPaperSize(s.Unit, s.Board)); env.PaperSize.CmpRational(limit) > 0 {
+		return ExactEnvelope{}, s.boardLimitError(env.PaperSize.Float64())
+	}
+
+	// the stock size comes first, as in Calculate
+	e := env.Envelope()
+
+	if _, err := newStockFit(e); err != nil {
+		return ExactEnvelope{}, err
+	}
+
+	if _, err := newLiner(e); err != nil {
+		return ExactEnvelope{}, err
+	}
+
+	if _, err := newInserts(e); err != nil {
+		return ExactEnvelope{}, err
+	}
+
+	return env, nil
+}
+
+// Envelope returns the floating point form of the calculation.
+func (e ExactEnvelope) Envelope() Envelope {
+	compensation := Exact{B: e.FoldAllowance.Mul(bigFraction(2, 1))}.Float64()
+
+	env := Envelope{
+		Spec:             e.Spec,
+		Stack:            e.Stack,
+		Margin:           e.Margin.Float64() + compensation,
+		LengthProjection: Exact{B: e.Length}.Float64(),
+		WidthProjection:  Exact{B: e.Width}.Float64(),
+		PaperSize:        e.PaperSize.Float64(),
+		PunchLocation:    e.PunchLocation.Float64(),
+		Orientation:      e.Spec.orientation(),
+		FoldAllowance:    e.FoldAllowance.Float64(),
+		FoldCompensation: compensation,
+	}
+
+	// the stock size, liner and inserts were checked by CalculateExact
+	env.Stock, _ = newStockFit(env)
+	env.Liner, _ = newLiner(env)
+	env.Inserts, _ = newInserts(env)
+	env.Flaps = newFlapAnalysis(env)
+	env.Instructions = newInstructions(env)
+	env.Sensitivity = newSensitivity(env)
+
+	return env
+}
@@ -0,0 +1,176 @@
+package calculate
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bigValue returns x to 256 bits of precision.
+func bigValue(x Exact) *big.Float {
+	const prec = 256
+
+	rat := func(r BigRational) *big.Float {
+		return new(big.Float).SetPrec(prec).SetRat(r.rat())
+	}
+
+	half := new(big.Float).SetPrec(prec).SetFloat64(0.5)
+	root := new(big.Float).SetPrec(prec).Sqrt(half)
+
+	return new(big.Float).SetPrec(prec).Add(rat(x.A), new(big.Float).SetPrec(prec).Mul(rat(x.B), root))
+}
+
+// bigRound returns the numerator of x rounded to the nearest multiple of 1/denom, halves up.
+func bigRound(x Exact, denom int64) int64 {
+	v := bigValue(x)
+	v.Mul(v, new(big.Float).SetInt64(denom))
+	v.Add(v, big.NewFloat(0.5))
+
+	k, _ := v.Int(nil)
+	if v.Sign() < 0 && !v.IsInt() {
+		k.Sub(k, big.NewInt(1))
+	}
+
+	return k.Int64()
+}
+
+func TestExact_CmpRational(t *testing.T) {
+	tests := []struct {
+		name string
+		x    Exact
+		r    BigRational
+		want int
+	}{
+		{name: "zero", x: Exact{}, r: BigRational{}, want: 0},
+		{name: "rational only", x: Exact{A: bigFraction(1, 2)}, r: bigFraction(1, 3), want: 1},
+		{name: "root only below", x: Exact{B: bigFraction(1, 1)}, r: bigFraction(71, 100), want: -1},
+		{name: "root only above", x: Exact{B: bigFraction(1, 1)}, r: bigFraction(70, 100), want: 1},
+		{name: "negative root", x: Exact{A: bigFraction(1, 1), B: bigFraction(-1, 1)}, r: bigFraction(29, 100), want: 1},
+		{name: "negative rational", x: Exact{A: bigFraction(-1, 1), B: bigFraction(2, 1)}, r: bigFraction(42, 100), want: -1},
+		{name: "both negative", x: Exact{A: bigFraction(-1, 1), B: bigFraction(-1, 1)}, r: BigRational{}, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.x.CmpRational(tt.r))
+			assert.Equal(t, tt.want, bigValue(tt.x).Cmp(bigValue(Exact{A: tt.r})))
+		})
+	}
+}
+
+func TestExact_Round(t *testing.T) {
+	x := Exact{A: bigFraction(11, 10), B: bigFraction(18, 1)} // 1.1 + 18·√½ = 13.8279...
+
+	assert.Equal(t, "13", x.Floor(1).Mixed())
+	assert.Equal(t, "14", x.Round(1).Mixed())
+	assert.Equal(t, "13 13/16", x.Round(16).Mixed())
+	assert.Equal(t, "13 83/100", x.RoundDecimal(2).Mixed())
+	assert.Equal(t, "13 8279/10000", x.RoundDecimal(4).Mixed())
+}
+
+func TestEnvelopeSpec_CalculateExact(t *testing.T) {
+	got, err := EnvelopeSpec{Length: 10, Width: 8, Loose: true}.CalculateExact()
+	require.NoError(t, err)
+
+	assert.Equal(t, "3", got.PaperSize.A.Mixed())
+	assert.Equal(t, "18", got.PaperSize.B.Mixed())
+	assert.Equal(t, "1 1/2", got.PunchLocation.A.Mixed())
+	assert.Equal(t, "8", got.PunchLocation.B.Mixed())
+	assert.Equal(t, "15.73", got.PaperSize.Format(2, 0))
+	assert.Equal(t, "7.16", got.PunchLocation.Format(2, 0))
+
+	env := got.Envelope()
+	assert.InDelta(t, 15.7279, env.PaperSize, 1e-4)
+	assert.InDelta(t, 7.1569, env.PunchLocation, 1e-4)
+
+	_, err = EnvelopeSpec{Length: 0, Width: 8}.CalculateExact()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+
+	_, err = EnvelopeSpec{Length: 25, Width: 20}.CalculateExact()
+	assert.ErrorIs(t, err, ErrBoardLimit)
+}
+
+// TestEnvelopeSpec_CalculateExactBounds checks every imperial envelope with
+// dimensions in sixteenths of an inch against a 256-bit calculation: fractional
+// results must round to the same sixteenth and float results must be within
+// MaxFloatError.
+func TestEnvelopeSpec_CalculateExactBounds(t *testing.T) {
+	const step = 1.0 / 16
+
+	for _, board := range []Board{BoardStandard, BoardMini} {
+		for _, loose := range []bool{false, true} {
+			for length := step; length <= 6; length += step {
+				for width := step; width <= length; width += 3 * step {
+					spec := EnvelopeSpec{Length: length, Width: width, Loose: loose, Board: board, Unit: UnitImperial}
+
+					got, err := spec.CalculateExact()
+					if err != nil {
+						require.ErrorIs(t, err, ErrBoardLimit)
+						continue
+					}
+
+					for _, x := range []Exact{got.PaperSize, got.PunchLocation} {
+						r := x.Round(16).rat()
+						require.Equal(t, bigRound(x, 16), r.Num().Int64()*(16/r.Denom().Int64()), "%v rounded to sixteenths", spec)
+
+						want, _ := bigValue(x).Float64()
+						require.LessOrEqual(t, math.Abs(x.Float64()-want)/want, MaxFloatError, "%v", spec)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestEnvelopeSpec_CalculateExactDecimals checks envelopes with long decimal
+// dimensions, whose exact forms need more than 64 bits, against a 256-bit
+// calculation and against the floating point result at six decimal places.
+func TestEnvelopeSpec_CalculateExactDecimals(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      EnvelopeSpec
+		wantPaper string
+		wantPunch string
+	}{
+		{
+			name:      "nine places",
+			spec:      EnvelopeSpec{Length: 5.123456789, Width: 7.987654321, Unit: UnitImperial},
+			wantPaper: "10.145956",
+			wantPunch: "4.060331",
+		},
+		{
+			name:      "four places",
+			spec:      EnvelopeSpec{Length: 5.1234, Width: 7.9876},
+			wantPaper: "11.470877",
+			wantPunch: "4.722791",
+		},
+		{
+			name:      "seventeen digits",
+			spec:      EnvelopeSpec{Length: 3.1415926535897931, Width: 2.7182818284590451, Loose: true, Caliper: 0.123456789},
+			wantPaper: "7.178476",
+			wantPunch: "3.439575",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const precision = 6
+
+			got, err := tt.spec.CalculateExact()
+			require.NoError(t, err)
+
+			env, err := tt.spec.Calculate()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantPaper, got.PaperSize.Format(precision, 0))
+			assert.Equal(t, tt.wantPunch, got.PunchLocation.Format(precision, 0))
+			assert.InEpsilon(t, env.PaperSize, got.PaperSize.Float64(), 1e-14)
+			assert.InEpsilon(t, env.PunchLocation, got.PunchLocation.Float64(), 1e-14)
+
+			for _, x := range []Exact{got.PaperSize, got.PunchLocation} {
+				assert.Equal(t, bigRound(x, 1e6), x.RoundDecimal(precision).Mul(bigFraction(1e6, 1)).rat().Num().Int64())
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+)
+
+// FlapAnalysis reports how far the flaps of a folded envelope reach and where
+// they overlap. The envelope is seen from the back with its long edges at the
+// top and bottom: the top flap closes it and the side and bottom flaps are
+// glued down first. Overlaps are negative where flaps leave a gap.
+type FlapAnalysis struct {
+	TopReach    float64 `json:"top_reach"`    // depth of the top flap from its fold
+	BottomReach float64 `json:"bottom_reach"` // depth of the bottom flap from its fold
+	SideReach   float64 `json:"side_reach"`   // depth of each side flap from its fold
+
+	SideOverlap       float64 `json:"side_overlap"`        // overlap of the two side flap tips
+	TopBottomOverlap  float64 `json:"top_bottom_overlap"`  // overlap of the top and bottom flap tips
+	SideBottomOverlap float64 `json:"side_bottom_overlap"` // overlap across the edges of each side flap and the bottom flap
+	TopSideOverlap    float64 `json:"top_side_overlap"`    // overlap across the edges of the top flap and each side flap
+
+	Covered  bool     `json:"covered"`            // the flaps cover the whole back of the envelope
+	Problems []string `json:"problems,omitempty"` // places where the flaps fail to meet
+}
+
+// newFlapAnalysis returns the flap analysis for an envelope, or nil if the spec does not ask for it.
+//
+// Each flap is the paper corner beyond a content edge. It folds over as a
+// right-angled triangle with its tip over the middle of the edge, so the edges
+// of neighbouring flaps are parallel and their overlap is measured square to them.
+func newFlapAnalysis(e Envelope) *FlapAnalysis {
+	if !e.Spec.CheckFlaps {
+		return nil
+	}
+
+	a := math.Min(e.LengthProjection, e.WidthProjection)
+	b := math.Max(e.LengthProjection, e.WidthProjection)
+
+	// content edges and the depth of the paper corner beyond each
+	paper, _, _ := e.Sheet()
+	long, short := b*math.Sqrt2, a*math.Sqrt2
+	closing := (paper - a) * math.Sqrt(0.5)
+	side := (paper - b) * math.Sqrt(0.5)
+
+	return analyzeFlaps(long, short, closing, closing, side)
+}
+
+// analyzeFlaps returns the flap analysis for content long x short with the given flap depths.
+func analyzeFlaps(long, short, top, bottom, side float64) *FlapAnalysis {
+	f := &FlapAnalysis{
+		TopReach:          top,
+		BottomReach:       bottom,
+		SideReach:         side,
+		SideOverlap:       2*side - long,
+		TopBottomOverlap:  top + bottom - short,
+		SideBottomOverlap: (side + bottom - (long+short)/2) * math.Sqrt(0.5),
+		TopSideOverlap:    (side + top - (long+short)/2) * math.Sqrt(0.5),
+	}
+
+	if f.SideBottomOverlap < 0 {
+		f.Problems = append(f.Problems, fmt.Sprintf("side and bottom flaps leave a gap of %.3g", -f.SideBottomOverlap))
+	}
+
+	if f.TopSideOverlap < 0 {
+		f.Problems = append(f.Problems, fmt.Sprintf("top and side flaps leave a gap of %.3g under the top flap", -f.TopSideOverlap))
+	}
+
+	// the middle is covered if either pair of opposite flaps meets, which
+	// always happens when the neighbouring flaps overlap
+	if f.TopBottomOverlap < 0 && f.SideOverlap < 0 {
+		f.Problems = append(f.Problems, fmt.Sprintf("bottom flap is %.3g short of the top flap", -f.TopBottomOverlap))
+	}
+
+	f.Covered = len(f.Problems) == 0
+
+	return f
+}
@@ -0,0 +1,82 @@
+package calculate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeSpec_CalculateFlaps(t *testing.T) {
+	const delta = 0.0001
+
+	env, err := EnvelopeSpec{Length: 22, Width: 11, CheckFlaps: true}.Calculate()
+	require.NoError(t, err)
+	require.NotNil(t, env.Flaps)
+
+	f := env.Flaps
+	assert.InDelta(t, 12.5556, f.TopReach, delta)
+	assert.InDelta(t, 12.5556, f.BottomReach, delta)
+	assert.InDelta(t, 7.0556, f.SideReach, delta)
+	assert.InDelta(t, -7.8887, f.SideOverlap, delta)
+	assert.InDelta(t, 14.1113, f.TopBottomOverlap, delta)
+
+	// neighbouring flaps overlap by twice the margin whatever the content
+	assert.InDelta(t, 2.2, f.SideBottomOverlap, 1e-9)
+	assert.InDelta(t, 2.2, f.TopSideOverlap, 1e-9)
+
+	assert.True(t, f.Covered)
+	assert.Empty(t, f.Problems)
+
+	exact, err := EnvelopeSpec{Length: 22, Width: 11, CheckFlaps: true}.CalculateExact()
+	require.NoError(t, err)
+	assert.InDelta(t, f.SideOverlap, exact.Envelope().Flaps.SideOverlap, 1e-9)
+
+	env, err = EnvelopeSpec{Length: 22, Width: 11}.Calculate()
+	require.NoError(t, err)
+	assert.Nil(t, env.Flaps)
+}
+
+func TestAnalyzeFlaps(t *testing.T) {
+	tests := []struct {
+		name                string
+		top, bottom, side   float64
+		wantCovered         bool
+		wantProblems        []string
+		wantSide, wantUnder float64
+	}{
+		{
+			name: "side flaps meet in the middle", top: 3, bottom: 3, side: 7,
+			wantCovered: true, wantSide: 0.7071, wantUnder: 0.7071,
+		},
+		{
+			name: "gap beside the bottom flap", top: 6, bottom: 4, side: 4,
+			wantProblems: []string{"side and bottom flaps leave a gap of 0.707"},
+			wantSide:     -0.7071, wantUnder: 0.7071,
+		},
+		{
+			name: "gap under the top flap", top: 4, bottom: 6, side: 4,
+			wantProblems: []string{"top and side flaps leave a gap of 0.707 under the top flap"},
+			wantSide:     0.7071, wantUnder: -0.7071,
+		},
+		{
+			name: "middle uncovered", top: 2, bottom: 2, side: 5,
+			wantProblems: []string{
+				"side and bottom flaps leave a gap of 1.41",
+				"top and side flaps leave a gap of 1.41 under the top flap",
+				"bottom flap is 2 short of the top flap",
+			},
+			wantSide: -1.4142, wantUnder: -1.4142,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := analyzeFlaps(12, 6, tt.top, tt.bottom, tt.side)
+
+			assert.Equal(t, tt.wantCovered, got.Covered)
+			assert.Equal(t, tt.wantProblems, got.Problems)
+			assert.InDelta(t, tt.wantSide, got.SideBottomOverlap, 0.0001)
+			assert.InDelta(t, tt.wantUnder, got.TopSideOverlap, 0.0001)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+)
+
+// Directions of a score line.
+const (
+	ScoreVertical   = "vertical"   // runs top to bottom, positioned from the left edge
+	ScoreHorizontal = "horizontal" // runs left to right, positioned from the top edge
+)
+
+// ScoreLine is a straight line scored across a sheet before it is folded.
+type ScoreLine struct {
+	Direction string  `json:"direction"` // vertical or horizontal
+	Position  float64 `json:"position"`  // distance from the left edge for vertical lines, or the top edge for horizontal lines
+}
+
+// scoreLines returns score lines in one direction at each position.
+func scoreLines(direction string, positions ...float64) []ScoreLine {
+	lines := make([]ScoreLine, len(positions))
+	for i, p := range positions {
+		lines[i] = ScoreLine{Direction: direction, Position: p}
+	}
+
+	return lines
+}
+
+// glueTab returns the width of the tab glued under the far edge of a bag or box.
+func glueTab(unit Unit) float64 {
+	if unit == UnitImperial {
+		return 0.5
+	}
+
+	return 1.5
+}
+
+// checkBoardLimit returns an error if the shorter side of a sheet is too long to score on the board.
+func checkBoardLimit(sheet Size, unit Unit, board Board) error {
+	if short := math.Min(sheet.Length, sheet.Width); short > MaxPaperSize(unit, board) {
+		return fmt.Errorf("%w: sheet %0.2f x %0.2f %s is larger than the %s board limit of %g %s",
+			ErrBoardLimit, sheet.Length, sheet.Width, unit, board, MaxPaperSize(unit, board), unit)
+	}
+
+	return nil
+}
+
+// GiftBagSpec describes a paper gift bag.
+type GiftBagSpec struct {
+	Width  float64 `json:"width"`  // front panel width
+	Depth  float64 `json:"depth"`  // side gusset width
+	Height float64 `json:"height"` // height from the base to the top fold
+	Board  Board   `json:"board"`
+	Unit   Unit    `json:"unit"`
+}
+
+// GiftBag is the result of a gift bag calculation.
+//
+// The sheet is laid out, left to right, as front, side, back, side and glue
+// tab, with the top hem above and the base flaps below. Each side is scored
+// down its middle so the gusset folds in.
+type GiftBag struct {
+	Spec       GiftBagSpec `json:"spec"`
+	Sheet      Size        `json:"sheet"`       // length across the panels, width from top to bottom
+	GlueTab    float64     `json:"glue_tab"`    // width of the tab glued under the front panel
+	Hem        float64     `json:"hem"`         // depth of the fold at the top of the bag
+	BaseFlap   float64     `json:"base_flap"`   // depth of the flaps folded to form the base
+	ScoreLines []ScoreLine `json:"score_lines"` // vertical lines from the left, then horizontal lines from the top
+}
+
+// Validate reports whether the spec can be used for a calculation.
+func (s GiftBagSpec) Validate() error {
+	if s.Width <= 0 {
+		return fmt.Errorf("%w: width must be greater than zero, got %g", ErrInvalidDimension, s.Width)
+	}
+
+	if s.Depth <= 0 {
+		return fmt.Errorf("%w: depth must be greater than zero, got %g", ErrInvalidDimension, s.Depth)
+	}
+
+	if s.Height <= 0 {
+		return fmt.Errorf("%w: height must be greater than zero, got %g", ErrInvalidDimension, s.Height)
+	}
+
+	if s.Depth > s.Width {
+		return fmt.Errorf("%w: depth %g must not be more than the width %g, or the base flaps cannot close",
+			ErrInvalidDimension, s.Depth, s.Width)
+	}
+
+	return nil
+}
+
+// Calculate calculates the sheet size and score lines for a gift bag.
+func (s GiftBagSpec) Calculate() (GiftBag, error) {
+	if err := s.Validate(); err != nil {
+		return GiftBag{}, err
+	}
+
+	const baseOverlap = 0.75 // base flap depth as a fraction of the depth; opposite flaps overlap by half the depth
+
+	hem := 2.5
+	if s.Unit == UnitImperial {
+		hem = 1
+	}
+
+	w, d := s.Width, s.Depth
+	tab := glueTab(s.Unit)
+	base := baseOverlap * d
+
+	bag := GiftBag{
+		Spec:     s,
+		Sheet:    Size{Length: 2*w + 2*d + tab, Width: hem + s.Height + base},
+		GlueTab:  tab,
+		Hem:      hem,
+		BaseFlap: base,
+	}
+
+	if err := checkBoardLimit(bag.Sheet, s.Unit, s.Board); err != nil {
+		return GiftBag{}, err
+	}
+
+	bag.ScoreLines = append(
+		scoreLines(ScoreVertical, w, w+d/2, w+d, 2*w+d, 2*w+1.5*d, 2*w+2*d),
+		scoreLines(ScoreHorizontal, hem, hem+s.Height)...)
+
+	return bag, nil
+}
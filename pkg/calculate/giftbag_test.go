@@ -0,0 +1,78 @@
+package calculate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGiftBagSpec_Calculate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    GiftBagSpec
+		want    GiftBag
+		wantErr error
+	}{
+		{
+			name: "imperial",
+			spec: GiftBagSpec{Width: 5, Depth: 2.5, Height: 7, Unit: UnitImperial},
+			want: GiftBag{
+				Sheet:    Size{Length: 15.5, Width: 9.875},
+				GlueTab:  0.5,
+				Hem:      1,
+				BaseFlap: 1.875,
+				ScoreLines: []ScoreLine{
+					{ScoreVertical, 5}, {ScoreVertical, 6.25}, {ScoreVertical, 7.5},
+					{ScoreVertical, 12.5}, {ScoreVertical, 13.75}, {ScoreVertical, 15},
+					{ScoreHorizontal, 1}, {ScoreHorizontal, 8},
+				},
+			},
+		},
+		{
+			name: "metric",
+			spec: GiftBagSpec{Width: 12, Depth: 4, Height: 20},
+			want: GiftBag{
+				Sheet:    Size{Length: 33.5, Width: 25.5},
+				GlueTab:  1.5,
+				Hem:      2.5,
+				BaseFlap: 3,
+				ScoreLines: []ScoreLine{
+					{ScoreVertical, 12}, {ScoreVertical, 14}, {ScoreVertical, 16},
+					{ScoreVertical, 28}, {ScoreVertical, 30}, {ScoreVertical, 32},
+					{ScoreHorizontal, 2.5}, {ScoreHorizontal, 22.5},
+				},
+			},
+		},
+		{
+			name:    "zero depth",
+			spec:    GiftBagSpec{Width: 12, Height: 20},
+			wantErr: errors.New("invalid dimension: depth must be greater than zero, got 0"),
+		},
+		{
+			name:    "deeper than wide",
+			spec:    GiftBagSpec{Width: 4, Depth: 5, Height: 20},
+			wantErr: ErrInvalidDimension,
+		},
+		{
+			name:    "too big for the mini board",
+			spec:    GiftBagSpec{Width: 12, Depth: 4, Height: 20, Board: BoardMini},
+			wantErr: ErrBoardLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Calculate()
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			tt.want.Spec = tt.spec
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+)
+
+// Size is the length and width of a rectangular piece of paper.
+type Size struct {
+	Length float64 `json:"length"`
+	Width  float64 `json:"width"`
+}
+
+// BellyBand is a strip of paper wrapped around the insert card, across its width.
+type BellyBand struct {
+	Length  float64 `json:"length"`  // length of the strip, including the overlap
+	Width   float64 `json:"width"`   // width of the strip
+	Overlap float64 `json:"overlap"` // length of the glued overlap where the ends meet
+}
+
+// Inserts are the pieces made to go inside an envelope: the insert card, a
+// layer mat for its front and a belly band around it.
+type Inserts struct {
+	Clearance float64   `json:"clearance"` // room left between the card and each content edge
+	Card      Size      `json:"card"`
+	Mat       Size      `json:"mat"`
+	BellyBand BellyBand `json:"belly_band"`
+}
+
+// Clearance returns the room left between an insert card and each content edge.
+//
+// Card makers usually cut an insert 1/8 in smaller than the card it goes in,
+// 1/16 in on each side, which is rounded up to 2 mm in metric. Loose content is
+// thicker, so its clearance is doubled.
+func Clearance(unit Unit, isLoose bool) float64 {
+	const (
+		clearanceMetric        float64 = 0.2
+		clearanceMetricLoose   float64 = 0.4
+		clearanceImperial      float64 = 0.0625
+		clearanceImperialLoose float64 = 0.125
+	)
+
+	switch {
+	case unit == UnitImperial && isLoose:
+		return clearanceImperialLoose
+	case unit == UnitImperial:
+		return clearanceImperial
+	case isLoose:
+		return clearanceMetricLoose
+	default:
+		return clearanceMetric
+	}
+}
+
+// insertSizes returns the mat border, belly band width and belly band overlap
+// for a unit. These are the usual layering border of 1/8 in, or 3 mm, and a
+// band and glued overlap of 1 in, or 2.5 cm.
+func insertSizes(unit Unit) (border, band, overlap float64) {
+	if unit == UnitImperial {
+		return 0.125, 1, 1
+	}
+
+	return 0.3, 2.5, 2.5
+}
+
+// newInserts returns the inserts for an envelope, or nil if the spec does not ask for them.
+func newInserts(e Envelope) (*Inserts, error) {
+	s := e.Spec
+	if !s.Inserts {
+		return nil, nil
+	}
+
+	clearance := Clearance(s.Unit, s.Loose)
+	border, band, overlap := insertSizes(s.Unit)
+
+	in := &Inserts{
+		Clearance: clearance,
+		Card:      Size{Length: s.Length - 2*clearance, Width: s.Width - 2*clearance},
+	}
+	in.Mat = Size{Length: in.Card.Length - 2*border, Width: in.Card.Width - 2*border}
+
+	if in.Mat.Length <= 0 || in.Mat.Width <= 0 {
+		return nil, fmt.Errorf("%w: %g x %g %s content is too small for an insert card and mat",
+			ErrInvalidDimension, s.Length, s.Width, s.Unit)
+	}
+
+	// the band wraps round the whole stack, so its edges add to the length
+	var thickness float64
+	if e.Stack != nil {
+		thickness = e.Stack.Thickness
+	}
+
+	in.BellyBand = BellyBand{
+		Length:  2*(in.Card.Width+thickness) + overlap,
+		Width:   math.Min(band, in.Card.Length/3),
+		Overlap: overlap,
+	}
+
+	return in, nil
+}
@@ -0,0 +1,94 @@
+package calculate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeSpec_CalculateInserts(t *testing.T) {
+	tests := []struct {
+		name string
+		spec EnvelopeSpec
+		want Inserts
+	}{
+		{
+			name: "metric snug",
+			spec: EnvelopeSpec{Length: 14.8, Width: 10.5, Inserts: true},
+			want: Inserts{
+				Clearance: 0.2,
+				Card:      Size{Length: 14.4, Width: 10.1},
+				Mat:       Size{Length: 13.8, Width: 9.5},
+				BellyBand: BellyBand{Length: 22.7, Width: 2.5, Overlap: 2.5},
+			},
+		},
+		{
+			name: "imperial loose",
+			spec: EnvelopeSpec{Length: 7, Width: 5, Loose: true, Unit: UnitImperial, Inserts: true},
+			want: Inserts{
+				Clearance: 0.125,
+				Card:      Size{Length: 6.75, Width: 4.75},
+				Mat:       Size{Length: 6.5, Width: 4.5},
+				BellyBand: BellyBand{Length: 10.5, Width: 1, Overlap: 1},
+			},
+		},
+		{
+			name: "narrow band on a small card",
+			spec: EnvelopeSpec{Length: 3.4, Width: 3, Inserts: true},
+			want: Inserts{
+				Clearance: 0.2,
+				Card:      Size{Length: 3, Width: 2.6},
+				Mat:       Size{Length: 2.4, Width: 2},
+				BellyBand: BellyBand{Length: 7.7, Width: 1, Overlap: 2.5},
+			},
+		},
+		{
+			name: "band around a stack",
+			spec: EnvelopeSpec{
+				Items:   []ContentItem{{Length: 7, Width: 5, Thickness: 0.1}, {Length: 5, Width: 3.5, Thickness: 0.2}},
+				Unit:    UnitImperial,
+				Inserts: true,
+			},
+			want: Inserts{
+				Clearance: 0.0625,
+				Card:      Size{Length: 7.175, Width: 5.175},
+				Mat:       Size{Length: 6.925, Width: 4.925},
+				BellyBand: BellyBand{Length: 11.95, Width: 1, Overlap: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := tt.spec.Calculate()
+			require.NoError(t, err)
+			require.NotNil(t, env.Inserts)
+
+			got := *env.Inserts
+			assert.Equal(t, tt.want.Clearance, got.Clearance)
+			assert.InDelta(t, tt.want.Card.Length, got.Card.Length, 1e-9)
+			assert.InDelta(t, tt.want.Card.Width, got.Card.Width, 1e-9)
+			assert.InDelta(t, tt.want.Mat.Length, got.Mat.Length, 1e-9)
+			assert.InDelta(t, tt.want.Mat.Width, got.Mat.Width, 1e-9)
+			assert.InDelta(t, tt.want.BellyBand.Length, got.BellyBand.Length, 1e-9)
+			assert.InDelta(t, tt.want.BellyBand.Width, got.BellyBand.Width, 1e-9)
+			assert.Equal(t, tt.want.BellyBand.Overlap, got.BellyBand.Overlap)
+
+			exact, err := tt.spec.CalculateExact()
+			require.NoError(t, err)
+			assert.Equal(t, env.Inserts, exact.Envelope().Inserts)
+		})
+	}
+}
+
+func TestEnvelopeSpec_CalculateInsertsErrors(t *testing.T) {
+	env, err := EnvelopeSpec{Length: 10, Width: 8}.Calculate()
+	assert.NoError(t, err)
+	assert.Nil(t, env.Inserts)
+
+	_, err = EnvelopeSpec{Length: 10, Width: 0.9, Inserts: true}.Calculate()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+
+	_, err = EnvelopeSpec{Length: 10, Width: 0.9, Inserts: true}.CalculateExact()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+}
@@ -0,0 +1,172 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+)
+
+// Actions in a sequence of board instructions.
+const (
+	ActionPlace  = "place"  // put the paper on the board with its edge or corner at Position
+	ActionPunch  = "punch"  // punch the paper where it lies
+	ActionScore  = "score"  // score a line, at Position on a scoring board
+	ActionRotate = "rotate" // turn the paper Rotation degrees counterclockwise
+	ActionAlign  = "align"  // line up the last score line with the punch board guide
+)
+
+// Step is one step of a board sequence.
+type Step struct {
+	Number   int     `json:"step"`
+	Action   string  `json:"action"`             // place, punch, score, rotate or align
+	Position float64 `json:"position,omitempty"` // ruler measurement for place and score steps
+	Rotation int     `json:"rotation,omitempty"` // degrees counterclockwise for rotate steps
+	Corner   bool    `json:"corner,omitempty"`   // the paper is placed by a corner instead of an edge
+	Point    int     `json:"point,omitempty"`    // punch point, 1 or 2, where each side has two; zero where it has one
+}
+
+// Describe returns the step as an instruction for a crafter, with lengths formatted by length.
+func (s Step) Describe(length func(float64) string) string {
+	switch s.Action {
+	case ActionPlace:
+		if s.Corner {
+			return "Turn the paper so a corner touches the top fence with its diagonal square to the fence"
+		}
+
+		if s.Point == 2 {
+			return "Slide the paper along the guide until its left edge is at " + length(s.Position) + " on the ruler, the second punch point"
+		}
+
+		return "Place the paper with its top edge against the guide and its left edge at " + length(s.Position) + " on the ruler"
+	case ActionPunch:
+		return "Punch"
+	case ActionScore:
+		if s.Position > 0 {
+			return "Score at " + length(s.Position) + " from the fence"
+		}
+
+		return "Score along the groove from the punch to the edge of the paper"
+	case ActionRotate:
+		return fmt.Sprintf("Rotate the paper %d° counterclockwise", s.Rotation)
+	case ActionAlign:
+		switch s.Point {
+		case 1:
+			return "Line up the score line from the first punch point on the last side with the guide"
+		case 2:
+			return "Line up the score line from the second punch point on the last side with the guide"
+		}
+
+		return "Line up the score line you just made with the guide"
+	default:
+		return s.Action
+	}
+}
+
+// Instructions are the steps to make an envelope or box blank on each kind of board.
+type Instructions struct {
+	PunchBoard   []Step `json:"punch_board"`   // the 1-2-3 method on an envelope punch board
+	ScoringBoard []Step `json:"scoring_board"` // scoring each fold on a plain scoring board
+}
+
+// sequence numbers a list of steps from one.
+func sequence(steps ...Step) []Step {
+	for i := range steps {
+		steps[i].Number = i + 1
+	}
+
+	return steps
+}
+
+// quarterTurn is a rotation by a quarter turn.
+var quarterTurn = Step{Action: ActionRotate, Rotation: 90}
+
+// punchBoardSteps returns the 1-2-3 method for a blank punched at each of
+// punches along every side. The first side is placed on the ruler; each later
+// punch is placed by lining up the score line from the same punch point on the
+// side before with the guide.
+func punchBoardSteps(punches ...float64) []Step {
+	var steps []Step
+
+	for side := 0; side < 4; side++ {
+		if side > 0 {
+			steps = append(steps, quarterTurn)
+		}
+
+		for i, p := range punches {
+			point := 0
+			if len(punches) > 1 {
+				point = i + 1
+			}
+
+			if side == 0 {
+				steps = append(steps, Step{Action: ActionPlace, Position: p, Point: point})
+			} else {
+				steps = append(steps, Step{Action: ActionAlign, Point: point})
+			}
+
+			steps = append(steps, Step{Action: ActionPunch}, Step{Action: ActionScore})
+		}
+	}
+
+	return sequence(steps...)
+}
+
+// scoringBoardSteps returns the scoring board sequence for a blank whose
+// corners, going round, alternate between those beyond the long content edges
+// and those beyond the short ones. Each score is placed by its distance from
+// the corner.
+func scoringBoardSteps(long, short []float64) []Step {
+	steps := []Step{{Action: ActionPlace, Corner: true}}
+
+	for corner := 0; corner < 4; corner++ {
+		if corner > 0 {
+			steps = append(steps, quarterTurn)
+		}
+
+		depths := long
+		if corner%2 == 1 {
+			depths = short
+		}
+
+		for _, d := range depths {
+			steps = append(steps, Step{Action: ActionScore, Position: d})
+		}
+	}
+
+	return sequence(steps...)
+}
+
+// newInstructions returns the board instructions for an envelope, or nil if
+// the spec does not ask for them. They are for the stock sheet if there is one.
+// On a scoring board each fold is scored square to a diagonal, as far from the
+// paper corner as the depth of its flap.
+func newInstructions(e Envelope) *Instructions {
+	if !e.Spec.Steps {
+		return nil
+	}
+
+	paper, punch, _ := e.Sheet()
+
+	a := math.Min(e.LengthProjection, e.WidthProjection)
+	b := math.Max(e.LengthProjection, e.WidthProjection)
+
+	return &Instructions{
+		PunchBoard:   punchBoardSteps(punch),
+		ScoringBoard: scoringBoardSteps([]float64{(paper - a) * math.Sqrt(0.5)}, []float64{(paper - b) * math.Sqrt(0.5)}),
+	}
+}
+
+// boxInstructions returns the board instructions for a box part. Each corner
+// is scored for the content edge and again, nearer the corner, for the top of
+// the side folded up from it.
+func boxInstructions(p BoxPart) *Instructions {
+	a := math.Min(p.Length, p.Width) * math.Sqrt(0.5)
+	b := math.Max(p.Length, p.Width) * math.Sqrt(0.5)
+
+	long := (p.PaperSize - a) * math.Sqrt(0.5)
+	short := (p.PaperSize - b) * math.Sqrt(0.5)
+
+	return &Instructions{
+		PunchBoard:   punchBoardSteps(p.PunchLocation, p.SecondPunch),
+		ScoringBoard: scoringBoardSteps([]float64{long - p.Height, long}, []float64{short - p.Height, short}),
+	}
+}
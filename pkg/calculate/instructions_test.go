@@ -0,0 +1,120 @@
+package calculate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeSpec_CalculateInstructions(t *testing.T) {
+	env, err := EnvelopeSpec{Length: 7, Width: 5, Unit: UnitImperial, Steps: true}.Calculate()
+	require.NoError(t, err)
+	require.NotNil(t, env.Instructions)
+
+	punch := env.Instructions.PunchBoard
+	require.Len(t, punch, 15)
+	assert.Equal(t, Step{Number: 1, Action: ActionPlace, Position: env.PunchLocation}, punch[0])
+
+	var actions []string
+	for i, s := range punch {
+		assert.Equal(t, i+1, s.Number)
+		actions = append(actions, s.Action)
+	}
+
+	assert.Equal(t, []string{
+		ActionPlace, ActionPunch, ActionScore,
+		ActionRotate, ActionAlign, ActionPunch, ActionScore,
+		ActionRotate, ActionAlign, ActionPunch, ActionScore,
+		ActionRotate, ActionAlign, ActionPunch, ActionScore,
+	}, actions)
+
+	score := env.Instructions.ScoringBoard
+	require.Len(t, score, 8)
+	assert.True(t, score[0].Corner)
+
+	// each score is as far from the corner as the depth of its flap
+	f := newFlapAnalysis(Envelope{
+		Spec:             EnvelopeSpec{CheckFlaps: true},
+		LengthProjection: env.LengthProjection,
+		WidthProjection:  env.WidthProjection,
+		PaperSize:        env.PaperSize,
+	})
+	for i, want := range []float64{f.TopReach, f.SideReach, f.BottomReach, f.SideReach} {
+		s := score[2*i+1]
+		assert.Equal(t, ActionScore, s.Action)
+		assert.InDelta(t, want, s.Position, 1e-9)
+	}
+
+	exact, err := EnvelopeSpec{Length: 7, Width: 5, Unit: UnitImperial, Steps: true}.CalculateExact()
+	require.NoError(t, err)
+	assert.Len(t, exact.Envelope().Instructions.PunchBoard, 15)
+
+	env, err = EnvelopeSpec{Length: 7, Width: 5, Unit: UnitImperial}.Calculate()
+	require.NoError(t, err)
+	assert.Nil(t, env.Instructions)
+}
+
+func TestEnvelopeSpec_CalculateInstructionsStock(t *testing.T) {
+	env, err := EnvelopeSpec{Length: 7, Width: 5, Unit: UnitImperial, Steps: true, StockSizes: []float64{12}}.Calculate()
+	require.NoError(t, err)
+
+	assert.Equal(t, env.Stock.PunchLocation, env.Instructions.PunchBoard[0].Position)
+	assert.Greater(t, env.Instructions.ScoringBoard[1].Position, 4.2)
+}
+
+func TestStep_Describe(t *testing.T) {
+	length := func(f float64) string { return fmt.Sprintf("%.2f", f) }
+
+	tests := []struct {
+		step Step
+		want string
+	}{
+		{Step{Action: ActionPlace, Position: 4}, "Place the paper with its top edge against the guide and its left edge at 4.00 on the ruler"},
+		{Step{Action: ActionPlace, Corner: true}, "Turn the paper so a corner touches the top fence with its diagonal square to the fence"},
+		{Step{Action: ActionPunch}, "Punch"},
+		{Step{Action: ActionScore}, "Score along the groove from the punch to the edge of the paper"},
+		{Step{Action: ActionScore, Position: 3.25}, "Score at 3.25 from the fence"},
+		{Step{Action: ActionRotate, Rotation: 90}, "Rotate the paper 90° counterclockwise"},
+		{Step{Action: ActionAlign}, "Line up the score line you just made with the guide"},
+		{Step{Action: ActionPlace, Position: 4.5, Point: 2}, "Slide the paper along the guide until its left edge is at 4.50 on the ruler, the second punch point"},
+		{Step{Action: ActionAlign, Point: 1}, "Line up the score line from the first punch point on the last side with the guide"},
+		{Step{Action: ActionAlign, Point: 2}, "Line up the score line from the second punch point on the last side with the guide"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.step.Action, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.step.Describe(length))
+		})
+	}
+}
+
+func TestBoxSpec_CalculateInstructions(t *testing.T) {
+	box, err := BoxSpec{Length: 4, Width: 3, Height: 1, Unit: UnitImperial, Lid: true, Steps: true}.Calculate()
+	require.NoError(t, err)
+	require.NotNil(t, box.Base.Instructions)
+	require.NotNil(t, box.Lid.Instructions)
+
+	punch := box.Base.Instructions.PunchBoard
+	require.Len(t, punch, 27)
+	assert.Equal(t, Step{Number: 1, Action: ActionPlace, Position: box.Base.PunchLocation, Point: 1}, punch[0])
+	assert.Equal(t, Step{Number: 4, Action: ActionPlace, Position: box.Base.SecondPunch, Point: 2}, punch[3])
+	assert.Equal(t, Step{Number: 7, Action: ActionRotate, Rotation: 90}, punch[6])
+	assert.Equal(t, Step{Number: 8, Action: ActionAlign, Point: 1}, punch[7])
+	assert.Equal(t, Step{Number: 11, Action: ActionAlign, Point: 2}, punch[10])
+
+	// each corner is scored for the content edge and the top of the side
+	score := box.Base.Instructions.ScoringBoard
+	require.Len(t, score, 12)
+	assert.InDelta(t, 2.6187, score[1].Position, 0.0001)
+	assert.InDelta(t, 3.6187, score[2].Position, 0.0001)
+	assert.Equal(t, ActionRotate, score[3].Action)
+	assert.InDelta(t, 2.1187, score[4].Position, 0.0001)
+	assert.InDelta(t, 3.1187, score[5].Position, 0.0001)
+
+	assert.Equal(t, box.Lid.SecondPunch, box.Lid.Instructions.PunchBoard[3].Position)
+
+	box, err = BoxSpec{Length: 4, Width: 3, Height: 1, Unit: UnitImperial}.Calculate()
+	require.NoError(t, err)
+	assert.Nil(t, box.Base.Instructions)
+}
@@ -0,0 +1,171 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Edges of a liner, in clockwise order from the top.
+const (
+	EdgeTop    = "top"
+	EdgeRight  = "right"
+	EdgeBottom = "bottom"
+	EdgeLeft   = "left"
+)
+
+// FoldMark is a place where a fold line crosses the edge of a liner.
+type FoldMark struct {
+	Edge     string  `json:"edge"`     // liner edge: top, right, bottom or left
+	Position float64 `json:"position"` // distance from the left end of the top and bottom edges, or the top end of the side edges
+}
+
+// Liner is a square of decorative paper glued inside an envelope blank, a fixed
+// inset from the paper edges, and folded with it.
+type Liner struct {
+	Inset float64 `json:"inset"` // distance from each paper edge to the liner edge
+	Size  float64 `json:"size"`  // side of the liner square
+
+	// Folds holds the fold lines that cross the liner, each as the pair of marks
+	// where it meets the liner edges. The folds run along the content edges.
+	Folds [][2]FoldMark `json:"folds"`
+
+	// FlapDepth is how far the liner reaches into the closing flap, the flap on
+	// a long side of the content, measured square to the fold.
+	FlapDepth float64 `json:"flap_depth"`
+}
+
+// Marks returns the fold marks on one edge of the liner, in order along the edge.
+func (l Liner) Marks(edge string) []float64 {
+	var marks []float64
+
+	for _, f := range l.Folds {
+		for _, m := range f {
+			if m.Edge == edge {
+				marks = append(marks, m.Position)
+			}
+		}
+	}
+
+	sort.Float64s(marks)
+
+	return marks
+}
+
+// newLiner returns the liner for an envelope, or nil if the spec has no liner inset.
+func newLiner(e Envelope) (*Liner, error) {
+	inset := e.Spec.LinerInset
+	if inset == 0 {
+		return nil, nil
+	}
+
+	paper, _, m := e.Sheet()
+
+	l := &Liner{Inset: inset, Size: paper - 2*inset}
+	if l.Size <= 0 {
+		return nil, fmt.Errorf("%w: liner inset %g leaves no liner on %0.2f %s paper",
+			ErrInvalidDimension, inset, paper, e.Spec.Unit)
+	}
+
+	a, b := e.Sides()
+
+	// the content edges, in liner coordinates, as u + v = c or u - v = c
+	folds := []struct {
+		sum bool
+		c   float64
+	}{
+		{sum: true, c: 2*(m-inset) + a},       // top left
+		{sum: false, c: a},                    // top right
+		{sum: true, c: 2*(m-inset) + a + 2*b}, // bottom right
+		{sum: false, c: -a},                   // bottom left
+	}
+
+	for _, f := range folds {
+		if marks, ok := clipFold(f.sum, f.c, l.Size); ok {
+			l.Folds = append(l.Folds, marks)
+		}
+	}
+
+	// the closing flap folds along a long content edge, with its tip in the
+	// liner corner beyond it
+	short := math.Min(a, b)
+	l.FlapDepth = math.Max(0, (l.Size-short)*math.Sqrt(0.5))
+
+	return l, nil
+}
+
+// clipFold returns the marks where the line u + v = c, or u - v = c if sum is
+// false, crosses the edges of a square of the given size.
+func clipFold(sum bool, c, size float64) ([2]FoldMark, bool) {
+	// v for a given u, and u for a given v
+	vAt := func(u float64) float64 {
+		if sum {
+			return c - u
+		}
+
+		return u - c
+	}
+
+	uAt := func(v float64) float64 {
+		if sum {
+			return c - v
+		}
+
+		return c + v
+	}
+
+	candidates := []FoldMark{
+		{Edge: EdgeTop, Position: uAt(0)},
+		{Edge: EdgeRight, Position: vAt(size)},
+		{Edge: EdgeBottom, Position: uAt(size)},
+		{Edge: EdgeLeft, Position: vAt(0)},
+	}
+
+	var marks []FoldMark
+
+	for _, m := range candidates {
+		if m.Position < -fitTolerance || m.Position > size+fitTolerance {
+			continue
+		}
+
+		// a fold through a corner meets two edges at the same point
+		if !containsPoint(marks, m, size) {
+			marks = append(marks, m)
+		}
+	}
+
+	if len(marks) < 2 {
+		return [2]FoldMark{}, false
+	}
+
+	return [2]FoldMark{marks[0], marks[1]}, true
+}
+
+// MarkPoint returns the position of a fold mark in liner coordinates, measured
+// from the liner's top left corner.
+func MarkPoint(m FoldMark, size float64) (float64, float64) {
+	switch m.Edge {
+	case EdgeRight:
+		return size, m.Position
+	case EdgeBottom:
+		return m.Position, size
+	case EdgeLeft:
+		return 0, m.Position
+	default:
+		return m.Position, 0
+	}
+}
+
+// containsPoint reports whether any of marks is at the same point as m.
+func containsPoint(marks []FoldMark, m FoldMark, size float64) bool {
+	x, y := MarkPoint(m, size)
+
+	for _, other := range marks {
+		ox, oy := MarkPoint(other, size)
+		if math.Abs(x-ox) < fitTolerance && math.Abs(y-oy) < fitTolerance {
+			return true
+		}
+	}
+
+	return false
+}
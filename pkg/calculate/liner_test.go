@@ -0,0 +1,88 @@
+package calculate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeSpec_CalculateLiner(t *testing.T) {
+	env, err := EnvelopeSpec{Length: 10, Width: 8, LinerInset: 0.5}.Calculate()
+	require.NoError(t, err)
+	require.NotNil(t, env.Liner)
+
+	l := env.Liner
+	assert.Equal(t, 0.5, l.Inset)
+	assert.InDelta(t, 13.928, l.Size, 0.001)
+	assert.InDelta(t, 5.848, l.FlapDepth, 0.001)
+
+	// each content edge crosses two liner edges
+	want := [][2]FoldMark{
+		{{EdgeTop, 6.857}, {EdgeLeft, 6.857}},
+		{{EdgeTop, 5.657}, {EdgeRight, 8.271}},
+		{{EdgeRight, 7.071}, {EdgeBottom, 7.071}},
+		{{EdgeBottom, 8.271}, {EdgeLeft, 5.657}},
+	}
+	require.Len(t, l.Folds, len(want))
+
+	for i, f := range l.Folds {
+		for j, m := range f {
+			assert.Equal(t, want[i][j].Edge, m.Edge, "fold %d mark %d", i, j)
+			assert.InDelta(t, want[i][j].Position, m.Position, 0.001, "fold %d mark %d", i, j)
+		}
+	}
+
+	top := l.Marks(EdgeTop)
+	require.Len(t, top, 2)
+	assert.InDelta(t, 5.657, top[0], 0.001)
+	assert.InDelta(t, 6.857, top[1], 0.001)
+}
+
+func TestEnvelopeSpec_CalculateLinerErrors(t *testing.T) {
+	env, err := EnvelopeSpec{Length: 10, Width: 8}.Calculate()
+	assert.NoError(t, err)
+	assert.Nil(t, env.Liner)
+
+	_, err = EnvelopeSpec{Length: 10, Width: 8, LinerInset: -1}.Calculate()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+
+	_, err = EnvelopeSpec{Length: 10, Width: 8, LinerInset: 8}.Calculate()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+
+	_, err = EnvelopeSpec{Length: 10, Width: 8, LinerInset: 8}.CalculateExact()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+}
+
+func TestExactEnvelope_Liner(t *testing.T) {
+	spec := EnvelopeSpec{Length: 5, Width: 3, Unit: UnitImperial, LinerInset: 0.25}
+
+	exact, err := spec.CalculateExact()
+	require.NoError(t, err)
+
+	env, err := spec.Calculate()
+	require.NoError(t, err)
+
+	require.NotNil(t, exact.Envelope().Liner)
+	assert.InDelta(t, env.Liner.Size, exact.Envelope().Liner.Size, 1e-9)
+}
+
+func TestMarkPoint(t *testing.T) {
+	tests := []struct {
+		mark  FoldMark
+		wantX float64
+		wantY float64
+	}{
+		{FoldMark{EdgeTop, 2}, 2, 0},
+		{FoldMark{EdgeRight, 2}, 10, 2},
+		{FoldMark{EdgeBottom, 2}, 2, 10},
+		{FoldMark{EdgeLeft, 2}, 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mark.Edge, func(t *testing.T) {
+			x, y := MarkPoint(tt.mark, 10)
+			assert.Equal(t, tt.wantX, x)
+			assert.Equal(t, tt.wantY, y)
+		})
+	}
+}
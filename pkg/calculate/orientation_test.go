@@ -0,0 +1,118 @@
+package calculate
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOrientation(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      Orientation
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "auto", arg: "auto", want: OrientationAuto, assertion: assert.NoError},
+		{name: "empty", arg: "", want: OrientationAuto, assertion: assert.NoError},
+		{name: "width", arg: "Width", want: OrientationWidth, assertion: assert.NoError},
+		{name: "length", arg: "l", want: OrientationLength, assertion: assert.NoError},
+		{name: "unknown", arg: "sideways", want: OrientationAuto, assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOrientation(tt.arg)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOrientation_Text(t *testing.T) {
+	for _, o := range []Orientation{OrientationAuto, OrientationWidth, OrientationLength} {
+		text, err := o.MarshalText()
+		require.NoError(t, err)
+
+		var got Orientation
+		require.NoError(t, got.UnmarshalText(text))
+		assert.Equal(t, o, got)
+	}
+
+	assert.Error(t, new(Orientation).UnmarshalText([]byte("up")))
+}
+
+func TestEnvelopeSpec_CalculateOrientation(t *testing.T) {
+	tests := []struct {
+		name          string
+		length, width float64
+		orientation   Orientation
+		want          Orientation
+	}{
+		{name: "auto with the width shorter", length: 7, width: 5, want: OrientationWidth},
+		{name: "auto with the length shorter", length: 5, width: 7, want: OrientationLength},
+		{name: "auto with square content", length: 6, width: 6, want: OrientationWidth},
+		{name: "forced length", length: 7, width: 5, orientation: OrientationLength, want: OrientationLength},
+		{name: "forced width", length: 5, width: 7, orientation: OrientationWidth, want: OrientationWidth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := EnvelopeSpec{Length: tt.length, Width: tt.width, Unit: UnitImperial, Orientation: tt.orientation, LinerInset: 0.5}
+
+			env, err := spec.Calculate()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, env.Orientation)
+
+			left, right := env.Sides()
+			measured := env.WidthProjection
+			if tt.want == OrientationLength {
+				measured = env.LengthProjection
+			}
+
+			assert.Equal(t, measured, left)
+			assert.InDelta(t, env.LengthProjection+env.WidthProjection, left+right, 1e-12)
+			assert.InDelta(t, env.Margin+measured, env.PunchLocation, 1e-12)
+
+			exact, err := spec.CalculateExact()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, exact.Envelope().Orientation)
+			assert.InDelta(t, env.PunchLocation, exact.Envelope().PunchLocation, 1e-9)
+
+			// the fold along the content edge running right from the top corner
+			// crosses the top of the liner as far along as the left edge projects
+			var found bool
+			for _, m := range env.Liner.Marks(EdgeTop) {
+				found = found || math.Abs(m-left) < 1e-9
+			}
+
+			assert.True(t, found, "no liner fold mark at %g", left)
+		})
+	}
+}
+
+func TestEnvelope_SidesWithoutOrientation(t *testing.T) {
+	left, right := Envelope{LengthProjection: 2, WidthProjection: 3}.Sides()
+
+	assert.Equal(t, 2.0, left)
+	assert.Equal(t, 3.0, right)
+}
+
+func TestEnvelope_OrientationJSON(t *testing.T) {
+	env, err := EnvelopeSpec{Length: 7, Width: 5, Orientation: OrientationLength}.Calculate()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(env)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"orientation":"length"`)
+
+	env, err = EnvelopeSpec{Length: 7, Width: 5}.Calculate()
+	require.NoError(t, err)
+
+	data, err = json.Marshal(env)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"orientation":"auto"`)
+	assert.Contains(t, string(data), `"orientation":"width"`)
+}
@@ -0,0 +1,75 @@
+package calculate
+
+import "fmt"
+
+// PillowBoxSpec describes a pillow box.
+type PillowBoxSpec struct {
+	Width  float64 `json:"width"`  // width of the box lying flat
+	Length float64 `json:"length"` // length of the box between the ends
+	Depth  float64 `json:"depth"`  // thickness of the box at its middle
+	Board  Board   `json:"board"`
+	Unit   Unit    `json:"unit"`
+}
+
+// PillowBox is the result of a pillow box calculation.
+//
+// The sheet is laid out, left to right, as front, back and glue tab. Each end
+// of the front and back panels is scored along an arc that bulges out past the
+// end of the panel; the flaps beyond the arcs fold in to close the box.
+type PillowBox struct {
+	Spec       PillowBoxSpec `json:"spec"`
+	Sheet      Size          `json:"sheet"`      // length across the panels, width from end to end
+	GlueTab    float64       `json:"glue_tab"`   // width of the tab glued under the front panel
+	ArcDepth   float64       `json:"arc_depth"`  // how far each end arc bulges past the end of its panel
+	ArcRadius  float64       `json:"arc_radius"` // radius of the end arcs
+	ScoreLines []ScoreLine   `json:"score_lines"`
+}
+
+// Validate reports whether the spec can be used for a calculation.
+func (s PillowBoxSpec) Validate() error {
+	if s.Width <= 0 {
+		return fmt.Errorf("%w: width must be greater than zero, got %g", ErrInvalidDimension, s.Width)
+	}
+
+	if s.Length <= 0 {
+		return fmt.Errorf("%w: length must be greater than zero, got %g", ErrInvalidDimension, s.Length)
+	}
+
+	if s.Depth <= 0 {
+		return fmt.Errorf("%w: depth must be greater than zero, got %g", ErrInvalidDimension, s.Depth)
+	}
+
+	// an arc deeper than half its chord is more than a semicircle and cannot fold flat
+	if s.Depth > s.Width {
+		return fmt.Errorf("%w: depth %g must not be more than the width %g", ErrInvalidDimension, s.Depth, s.Width)
+	}
+
+	return nil
+}
+
+// Calculate calculates the sheet size, end arcs and score lines for a pillow box.
+func (s PillowBoxSpec) Calculate() (PillowBox, error) {
+	if err := s.Validate(); err != nil {
+		return PillowBox{}, err
+	}
+
+	w := s.Width
+	tab := glueTab(s.Unit)
+	depth := s.Depth / 2 // each end arc opens the box by half its thickness
+
+	box := PillowBox{
+		Spec:      s,
+		Sheet:     Size{Length: 2*w + tab, Width: s.Length + 2*depth},
+		GlueTab:   tab,
+		ArcDepth:  depth,
+		ArcRadius: (w*w/4 + depth*depth) / (2 * depth),
+	}
+
+	if err := checkBoardLimit(box.Sheet, s.Unit, s.Board); err != nil {
+		return PillowBox{}, err
+	}
+
+	box.ScoreLines = scoreLines(ScoreVertical, w, 2*w)
+
+	return box, nil
+}
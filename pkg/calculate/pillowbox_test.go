@@ -0,0 +1,70 @@
+package calculate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPillowBoxSpec_Calculate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    PillowBoxSpec
+		want    PillowBox
+		wantErr error
+	}{
+		{
+			name: "imperial",
+			spec: PillowBoxSpec{Width: 4, Length: 6, Depth: 1.5, Unit: UnitImperial},
+			want: PillowBox{
+				Sheet:      Size{Length: 8.5, Width: 7.5},
+				GlueTab:    0.5,
+				ArcDepth:   0.75,
+				ArcRadius:  3.0416666666666665,
+				ScoreLines: []ScoreLine{{ScoreVertical, 4}, {ScoreVertical, 8}},
+			},
+		},
+		{
+			name: "semicircle ends",
+			spec: PillowBoxSpec{Width: 8, Length: 10, Depth: 8},
+			want: PillowBox{
+				Sheet:      Size{Length: 17.5, Width: 18},
+				GlueTab:    1.5,
+				ArcDepth:   4,
+				ArcRadius:  4,
+				ScoreLines: []ScoreLine{{ScoreVertical, 8}, {ScoreVertical, 16}},
+			},
+		},
+		{
+			name:    "negative length",
+			spec:    PillowBoxSpec{Width: 8, Length: -1, Depth: 2},
+			wantErr: errors.New("invalid dimension: length must be greater than zero, got -1"),
+		},
+		{
+			name:    "deeper than wide",
+			spec:    PillowBoxSpec{Width: 4, Length: 10, Depth: 5},
+			wantErr: ErrInvalidDimension,
+		},
+		{
+			name:    "too big for the board",
+			spec:    PillowBoxSpec{Width: 16, Length: 30, Depth: 4},
+			wantErr: ErrBoardLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Calculate()
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			tt.want.Spec = tt.spec
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
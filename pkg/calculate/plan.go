@@ -0,0 +1,171 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// StockSheet is a sheet of paper as it is bought.
+type StockSheet struct {
+	Name   string     `json:"name,omitempty"`
+	Width  float64    `json:"width"`
+	Height float64    `json:"height"`
+	Unit   LengthUnit `json:"unit"`
+}
+
+func (s StockSheet) String() string {
+	size := fmt.Sprintf("%g x %g %s", s.Width, s.Height, s.Unit)
+	if s.Name == "" {
+		return size
+	}
+
+	return s.Name + " (" + size + ")"
+}
+
+// standardStock lists the stock sizes that can be given by name.
+var standardStock = []StockSheet{
+	{Name: "A4", Width: 210, Height: 297, Unit: Millimetre},
+	{Name: "A3", Width: 297, Height: 420, Unit: Millimetre},
+	{Name: "Letter", Width: 8.5, Height: 11, Unit: Inch},
+	{Name: "Legal", Width: 8.5, Height: 14, Unit: Inch},
+	{Name: "Tabloid", Width: 11, Height: 17, Unit: Inch},
+}
+
+// ParseStockSheet parses a stock size such as "A4", "letter", "12x12in" or
+// "30.5 x 30.5 cm". A unit written once applies to both sides, and sizes
+// without a unit are measured in def.
+func ParseStockSheet(s string, def LengthUnit) (StockSheet, error) {
+	for _, sheet := range standardStock {
+		if strings.EqualFold(strings.TrimSpace(s), sheet.Name) {
+			return sheet, nil
+		}
+	}
+
+	w, h, ok := strings.Cut(strings.ReplaceAll(strings.ToLower(s), "×", "x"), "x")
+	if !ok {
+		return StockSheet{}, fmt.Errorf("%w: stock %q: expected a name or width x height", ErrSyntax, s)
+	}
+
+	// a unit given once, as in "12x12in" or "12in x 12", applies to both sides
+	sides, err := parseSides([]string{w, h}, def)
+	if err != nil {
+		return StockSheet{}, err
+	}
+
+	width, height := sides[0], sides[1]
+
+	sheet := StockSheet{Width: width.To(height.Unit).Float64(), Height: height.Float64(), Unit: height.Unit}
+	if sheet.Width <= 0 || sheet.Height <= 0 {
+		return StockSheet{}, fmt.Errorf("%w: stock %q must have a positive width and height", ErrInvalidDimension, s)
+	}
+
+	return sheet, nil
+}
+
+// In returns the sheet measured in another unit.
+func (s StockSheet) In(u LengthUnit) StockSheet {
+	return StockSheet{Name: s.Name, Width: convertFloat(s.Width, s.Unit, u), Height: convertFloat(s.Height, s.Unit, u), Unit: u}
+}
+
+// convertFloat converts a length between units, exactly when it has a short decimal form.
+func convertFloat(f float64, from, to LengthUnit) float64 {
+	if from == to {
+		return f
+	}
+
+	r, err := BigRationalFromFloat(f)
+	if err != nil {
+		return f * Measurement{Value: bigFraction(1, 1), Unit: from}.To(to).Float64()
+	}
+
+	return Measurement{Value: r, Unit: from}.To(to).Float64()
+}
+
+// Cut is a straight guillotine cut across a piece of paper.
+type Cut struct {
+	Piece    string  `json:"piece"`    // piece being cut: "sheet" or "strip"
+	Position float64 `json:"position"` // distance from the left edge of a sheet or the top edge of a strip
+	Length   float64 `json:"length"`   // length of the cut
+	Count    int     `json:"count"`    // number of pieces that need this cut
+}
+
+// Offcut is a piece of paper left over after the blanks are cut.
+type Offcut struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// SheetPlan describes how square envelope blanks are cut from a stock sheet.
+type SheetPlan struct {
+	Blank    float64    `json:"blank"` // side of the square blank
+	Unit     LengthUnit `json:"unit"`
+	Stock    StockSheet `json:"stock"` // stock sheet, measured in Unit
+	Columns  int        `json:"columns"`
+	Rows     int        `json:"rows"`
+	PerSheet int        `json:"per_sheet"`
+	Cuts     []Cut      `json:"cuts"`
+	Offcuts  []Offcut   `json:"offcuts"`
+}
+
+// fitTolerance allows a blank that matches the stock size to within rounding error to fit.
+const fitTolerance = 1e-9
+
+// fit returns how many lengths of size fit in total.
+func fit(total, size float64) int {
+	return int(math.Floor(total/size + fitTolerance))
+}
+
+// PlanSheet lays out square blanks of the given size on a stock sheet in a grid
+// and returns the cuts needed to separate them. The blank is measured in unit.
+func PlanSheet(blank float64, unit LengthUnit, stock StockSheet) (SheetPlan, error) {
+	if blank <= 0 {
+		return SheetPlan{}, fmt.Errorf("%w: blank size must be positive, got %g", ErrInvalidDimension, blank)
+	}
+
+	stock = stock.In(unit)
+
+	p := SheetPlan{
+		Blank:   blank,
+		Unit:    unit,
+		Stock:   stock,
+		Columns: fit(stock.Width, blank),
+		Rows:    fit(stock.Height, blank),
+	}
+
+	p.PerSheet = p.Columns * p.Rows
+	if p.PerSheet == 0 {
+		return SheetPlan{}, fmt.Errorf("%w: %g %s blank does not fit on %s stock", ErrInvalidDimension, blank, unit, stock)
+	}
+
+	usedWidth := float64(p.Columns) * blank
+	usedHeight := float64(p.Rows) * blank
+
+	// cut the sheet into strips, then each strip into blanks
+	for i := 1; i <= p.Columns; i++ {
+		if x := float64(i) * blank; x < stock.Width-fitTolerance {
+			p.Cuts = append(p.Cuts, Cut{Piece: "sheet", Position: x, Length: stock.Height, Count: 1})
+		}
+	}
+
+	for i := 1; i <= p.Rows; i++ {
+		if y := float64(i) * blank; y < stock.Height-fitTolerance {
+			p.Cuts = append(p.Cuts, Cut{Piece: "strip", Position: y, Length: blank, Count: p.Columns})
+		}
+	}
+
+	if w := stock.Width - usedWidth; w > fitTolerance {
+		p.Offcuts = append(p.Offcuts, Offcut{Width: w, Height: stock.Height})
+	}
+
+	if h := stock.Height - usedHeight; h > fitTolerance {
+		p.Offcuts = append(p.Offcuts, Offcut{Width: usedWidth, Height: h})
+	}
+
+	return p, nil
+}
+
+// SheetsFor returns the number of stock sheets needed for qty blanks.
+func (p SheetPlan) SheetsFor(qty int) int {
+	return (qty + p.PerSheet - 1) / p.PerSheet
+}
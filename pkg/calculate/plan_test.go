@@ -0,0 +1,79 @@
+package calculate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStockSheet(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      StockSheet
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "name", arg: "a4", want: StockSheet{Name: "A4", Width: 210, Height: 297, Unit: Millimetre}, assertion: assert.NoError},
+		{name: "letter", arg: " Letter ", want: StockSheet{Name: "Letter", Width: 8.5, Height: 11, Unit: Inch}, assertion: assert.NoError},
+		{name: "shared unit", arg: "12x12in", want: StockSheet{Width: 12, Height: 12, Unit: Inch}, assertion: assert.NoError},
+		{name: "leading unit", arg: "12in x 12", want: StockSheet{Width: 12, Height: 12, Unit: Inch}, assertion: assert.NoError},
+		{name: "two units", arg: "305mm × 30.5 cm", want: StockSheet{Width: 30.5, Height: 30.5, Unit: Centimetre}, assertion: assert.NoError},
+		{name: "default unit", arg: "20 x 30", want: StockSheet{Width: 20, Height: 30, Unit: Centimetre}, assertion: assert.NoError},
+		{name: "unknown name", arg: "B5", assertion: assert.Error},
+		{name: "zero side", arg: "0x12in", assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStockSheet(tt.arg, Centimetre)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStockSheet_In(t *testing.T) {
+	a3 := StockSheet{Name: "A3", Width: 297, Height: 420, Unit: Millimetre}
+
+	assert.Equal(t, StockSheet{Name: "A3", Width: 29.7, Height: 42, Unit: Centimetre}, a3.In(Centimetre))
+	assert.Equal(t, StockSheet{Width: 304.8, Height: 304.8, Unit: Millimetre}, StockSheet{Width: 12, Height: 12, Unit: Inch}.In(Millimetre))
+}
+
+func TestPlanSheet(t *testing.T) {
+	stock := StockSheet{Width: 12, Height: 12, Unit: Inch}
+
+	got, err := PlanSheet(4.5, Inch, stock)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got.Columns)
+	assert.Equal(t, 2, got.Rows)
+	assert.Equal(t, 4, got.PerSheet)
+	assert.Equal(t, []Cut{
+		{Piece: "sheet", Position: 4.5, Length: 12, Count: 1},
+		{Piece: "sheet", Position: 9, Length: 12, Count: 1},
+		{Piece: "strip", Position: 4.5, Length: 4.5, Count: 2},
+		{Piece: "strip", Position: 9, Length: 4.5, Count: 2},
+	}, got.Cuts)
+	assert.Equal(t, []Offcut{{Width: 3, Height: 12}, {Width: 9, Height: 3}}, got.Offcuts)
+	assert.Equal(t, 10, got.SheetsFor(40))
+	assert.Equal(t, 1, got.SheetsFor(4))
+	assert.Equal(t, 2, got.SheetsFor(5))
+}
+
+func TestPlanSheet_ExactFit(t *testing.T) {
+	got, err := PlanSheet(6, Inch, StockSheet{Width: 12, Height: 12, Unit: Inch})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, got.PerSheet)
+	assert.Equal(t, []Cut{
+		{Piece: "sheet", Position: 6, Length: 12, Count: 1},
+		{Piece: "strip", Position: 6, Length: 6, Count: 2},
+	}, got.Cuts)
+	assert.Empty(t, got.Offcuts)
+}
+
+func TestPlanSheet_Errors(t *testing.T) {
+	_, err := PlanSheet(0, Inch, StockSheet{Width: 12, Height: 12, Unit: Inch})
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+
+	_, err = PlanSheet(25, Centimetre, StockSheet{Name: "A4", Width: 210, Height: 297, Unit: Millimetre})
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+}
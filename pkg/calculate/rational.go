@@ -0,0 +1,371 @@
+package calculate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidRational is returned for a mixed number that is malformed or out of range.
+var ErrInvalidRational = errors.New("invalid rational")
+
+// NewRational returns the mixed number i n/d in lowest terms. The sign is carried
+// by i, or by n when i is zero, so -1 1/2 is NewRational(-1, 1, 2) and -1/2 is
+// NewRational(0, -1, 2). A whole number has n and d both zero.
+func NewRational(i, n, d int64) (Rational, error) {
+	switch {
+	case n == 0 && d == 0:
+		return Rational{i: i}, nil
+	case d <= 0:
+		return Rational{}, fmt.Errorf("%w: denominator must be positive, got %d", ErrInvalidRational, d)
+	case i != 0 && n < 0:
+		return Rational{}, fmt.Errorf("%w: numerator must not be negative when there is a whole part", ErrInvalidRational)
+	case AbsInt64(n) >= d:
+		return Rational{}, fmt.Errorf("%w: fraction %d/%d is not proper", ErrInvalidRational, n, d)
+	case absUint64(i) > uint64((math.MaxInt64-d)/d):
+		return Rational{}, fmt.Errorf("%w: %d %d/%d is out of range", ErrInvalidRational, i, n, d)
+	}
+
+	if i < 0 {
+		return newFraction(i*d-n, d), nil
+	}
+
+	return newFraction(i*d+n, d), nil
+}
+
+// ParseRational parses a mixed number such as "1 1/2", "-3/4" or "2", the
+// "1 + 1/2" form written by String, or a decimal such as "1.5".
+func ParseRational(s string) (Rational, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Rational{}, fmt.Errorf("%w: empty string", ErrInvalidRational)
+	}
+
+	if !strings.Contains(s, "/") {
+		r, err := ParseDecimal(s)
+		if err != nil {
+			return Rational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+		}
+
+		return r, nil
+	}
+
+	whole, frac, ok := splitMixed(s)
+	if !ok {
+		return Rational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+	}
+
+	var i int64
+
+	if whole != "" {
+		w, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil {
+			return Rational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+		}
+
+		i = w
+	}
+
+	num, den, _ := strings.Cut(frac, "/")
+
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil {
+		return Rational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+	}
+
+	d, err := strconv.ParseInt(den, 10, 64)
+	if err != nil {
+		return Rational{}, fmt.Errorf("%w: %q", ErrInvalidRational, s)
+	}
+
+	// a minus sign on the whole part applies to the whole number
+	if i == 0 && strings.HasPrefix(s, "-") && n > 0 {
+		n = -n
+	}
+
+	r, err := NewRational(i, n, d)
+	if err != nil {
+		return Rational{}, fmt.Errorf("parsing %q: %w", s, err)
+	}
+
+	return r, nil
+}
+
+// splitMixed splits a mixed number into its whole part, which may be empty, and its fraction.
+func splitMixed(s string) (string, string, bool) {
+	fields := strings.Fields(s)
+
+	// drop the separator written by String
+	if len(fields) == 3 && fields[1] == "+" {
+		fields = []string{fields[0], fields[2]}
+	}
+
+	switch len(fields) {
+	case 1:
+		return "", fields[0], true
+	case 2:
+		return fields[0], fields[1], true
+	default:
+		return "", "", false
+	}
+}
+
+// fraction returns r as a numerator and positive denominator.
+func (r Rational) fraction() (int64, int64) {
+	if r.n == 0 || r.d == 0 {
+		return r.i, 1
+	}
+
+	n, d := r.n, r.d
+	if d < 0 {
+		n, d = -n, -d
+	}
+
+	if r.i < 0 {
+		return r.i*d - n, d
+	}
+
+	return r.i*d + n, d
+}
+
+// newFraction returns the Rational p/q in lowest terms.
+func newFraction(p, q int64) Rational {
+	if q < 0 {
+		p, q = -p, -q
+	}
+
+	if g := gcd(p, q); g > 1 {
+		p /= g
+		q /= g
+	}
+
+	r := Rational{i: p / q}
+
+	rem := p % q
+	if rem == 0 {
+		return r
+	}
+
+	// a negative mixed number keeps its sign on the integer part
+	if r.i < 0 {
+		rem = -rem
+	}
+
+	r.n, r.d = rem, q
+
+	return r
+}
+
+// Add returns r + s. Like the other arithmetic methods, it returns an error
+// wrapping ErrRange if the result does not fit in a Rational; use BigRational
+// for values that might not.
+func (r Rational) Add(s Rational) (Rational, error) {
+	p1, q1 := r.fraction()
+	p2, q2 := s.fraction()
+
+	// q1 and q2 share g, so the common denominator is q1 × q2/g
+	g := gcd(q1, q2)
+
+	a, ok1 := mulInt64(p1, q2/g)
+	b, ok2 := mulInt64(p2, q1/g)
+	p, ok3 := addInt64(a, b)
+	q, ok4 := mulInt64(q1, q2/g)
+
+	if ok1 && ok2 && ok3 && ok4 {
+		return newFraction(p, q), nil
+	}
+
+	// a result in lowest terms can fit when the working values do not
+	return NewBigRational(r).Add(NewBigRational(s)).Rational()
+}
+
+// Sub returns r - s.
+func (r Rational) Sub(s Rational) (Rational, error) {
+	n, err := s.Neg()
+	if err != nil {
+		return NewBigRational(r).Sub(NewBigRational(s)).Rational()
+	}
+
+	return r.Add(n)
+}
+
+// Mul returns r × s.
+func (r Rational) Mul(s Rational) (Rational, error) {
+	p1, q1 := r.fraction()
+	p2, q2 := s.fraction()
+
+	// cancelling across the fractions leaves the product in lowest terms
+	g1, g2 := gcd(p1, q2), gcd(p2, q1)
+
+	p, ok1 := mulInt64(p1/g1, p2/g2)
+	q, ok2 := mulInt64(q1/g2, q2/g1)
+
+	if ok1 && ok2 {
+		return newFraction(p, q), nil
+	}
+
+	return NewBigRational(r).Mul(NewBigRational(s)).Rational()
+}
+
+// Neg returns -r.
+func (r Rational) Neg() (Rational, error) {
+	p, q := r.fraction()
+	if p == math.MinInt64 {
+		return Rational{}, fmt.Errorf("%w: %s", ErrRange, NewBigRational(r).Neg().Mixed())
+	}
+
+	return newFraction(-p, q), nil
+}
+
+// mulInt64 returns a × b and whether it fits in an int64.
+func mulInt64(a, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(absUint64(a), absUint64(b))
+
+	switch {
+	case hi != 0:
+		return 0, false
+	case (a < 0) != (b < 0):
+		// the magnitude of a negative int64 can be one more than MaxInt64
+		return -int64(lo), lo <= 1<<63
+	default:
+		return int64(lo), lo <= math.MaxInt64
+	}
+}
+
+// addInt64 returns a + b and whether it fits in an int64.
+func addInt64(a, b int64) (int64, bool) {
+	c := a + b
+
+	return c, (c > a) == (b > 0)
+}
+
+// absUint64 returns the magnitude of i, which fits in a uint64 even for math.MinInt64.
+func absUint64(i int64) uint64 {
+	if i < 0 {
+		return -uint64(i)
+	}
+
+	return uint64(i)
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of r.
+func (r Rational) Sign() int {
+	p, _ := r.fraction()
+
+	switch {
+	case p < 0:
+		return -1
+	case p > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Cmp returns -1, 0 or +1 depending on whether r is less than, equal to or greater than s.
+func (r Rational) Cmp(s Rational) int {
+	p1, q1 := r.fraction()
+	p2, q2 := s.fraction()
+
+	a, ok1 := mulInt64(p1, q2)
+	b, ok2 := mulInt64(p2, q1)
+
+	switch {
+	case !ok1 || !ok2:
+		return NewBigRational(r).Cmp(NewBigRational(s))
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Float64 returns the nearest float64 value to r.
+func (r Rational) Float64() float64 {
+	p, q := r.fraction()
+
+	return float64(p) / float64(q)
+}
+
+// Mixed returns r as a mixed number, such as "1 1/2", "-3/4" or "2".
+func (r Rational) Mixed() string {
+	const base10 = 10
+
+	p, q := r.fraction()
+	if q == 1 {
+		return strconv.FormatInt(p, base10)
+	}
+
+	var s string
+	if p < 0 {
+		s = "-"
+		p = -p
+	}
+
+	if i := p / q; i != 0 {
+		s += strconv.FormatInt(i, base10) + " "
+	}
+
+	return s + strconv.FormatInt(p%q, base10) + "/" + strconv.FormatInt(q, base10)
+}
+
+// MarshalText implements encoding.TextMarshaler using the mixed number form.
+func (r Rational) MarshalText() ([]byte, error) {
+	return []byte(r.Mixed()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts any form read by ParseRational.
+func (r *Rational) UnmarshalText(text []byte) error {
+	v, err := ParseRational(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = v
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The value is written as a mixed number
+// string, which holds any Rational exactly.
+func (r Rational) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Mixed())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a mixed number string or
+// a JSON number, which is read from its decimal text without rounding.
+func (r *Rational) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return r.UnmarshalText([]byte(s))
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRational, data)
+	}
+
+	return r.UnmarshalText([]byte(num))
+}
+
+// MarshalYAML implements yaml.Marshaler using the mixed number form.
+func (r Rational) MarshalYAML() (any, error) {
+	return r.Mixed(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. It accepts a mixed number or a decimal.
+func (r *Rational) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%w: line %d: expected a single value", ErrInvalidRational, node.Line)
+	}
+
+	return r.UnmarshalText([]byte(node.Value))
+}
@@ -0,0 +1,275 @@
+package calculate
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_newFraction(t *testing.T) {
+	tests := []struct {
+		name string
+		p, q int64
+		want Rational
+	}{
+		{name: "zero", p: 0, q: 5, want: Rational{}},
+		{name: "integer", p: 10, q: 5, want: Rational{i: 2}},
+		{name: "proper", p: 2, q: 4, want: Rational{n: 1, d: 2}},
+		{name: "mixed", p: 6, q: 5, want: Rational{i: 1, n: 1, d: 5}},
+		{name: "negative proper", p: -1, q: 5, want: Rational{n: -1, d: 5}},
+		{name: "negative mixed", p: -6, q: 5, want: Rational{i: -1, n: 1, d: 5}},
+		{name: "negative denominator", p: 6, q: -5, want: Rational{i: -1, n: 1, d: 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newFraction(tt.p, tt.q)
+
+			assert.Equal(t, tt.want, got)
+
+			// the value is unchanged
+			p, q := got.fraction()
+			assert.Equal(t, float64(tt.p)/float64(tt.q), float64(p)/float64(q))
+		})
+	}
+}
+
+func TestRational_Arithmetic(t *testing.T) {
+	half := newFraction(1, 2)
+	third := newFraction(1, 3)
+	negSixFifths := newFraction(-6, 5)
+
+	tests := []struct {
+		name string
+		op   func() (Rational, error)
+		want Rational
+	}{
+		{name: "add", op: func() (Rational, error) { return half.Add(third) }, want: newFraction(5, 6)},
+		{name: "sub", op: func() (Rational, error) { return half.Sub(third) }, want: newFraction(1, 6)},
+		{name: "mul", op: func() (Rational, error) { return half.Mul(third) }, want: newFraction(1, 6)},
+		{name: "add negative", op: func() (Rational, error) { return half.Add(negSixFifths) }, want: newFraction(-7, 10)},
+		{name: "neg", op: negSixFifths.Neg, want: newFraction(6, 5)},
+		{name: "mul negative", op: func() (Rational, error) { return negSixFifths.Mul(half) }, want: newFraction(-3, 5)},
+		{name: "mul cancels", op: func() (Rational, error) { return newFraction(4, 9).Mul(newFraction(3, 8)) }, want: newFraction(1, 6)},
+		{name: "min int64", op: func() (Rational, error) { return newFraction(math.MinInt64+1, 1).Sub(newFraction(1, 1)) }, want: Rational{i: math.MinInt64}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, 1, half.Cmp(third))
+	assert.Equal(t, -1, negSixFifths.Cmp(third))
+	assert.Equal(t, 0, half.Cmp(newFraction(2, 4)))
+
+	assert.Equal(t, -1, negSixFifths.Sign())
+	assert.Equal(t, 0, Rational{}.Sign())
+	assert.InDelta(t, -1.2, negSixFifths.Float64(), 1e-15)
+}
+
+func TestRational_Overflow(t *testing.T) {
+	large := newFraction(math.MaxInt64, 1)
+	negLarge := newFraction(-math.MaxInt64, 1)
+	tiny := newFraction(1, math.MaxInt64)
+
+	tests := []struct {
+		name      string
+		op        func() (Rational, error)
+		want      Rational
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "add", op: func() (Rational, error) { return large.Add(large) }, assertion: outOfRange},
+		{name: "sub", op: func() (Rational, error) { return negLarge.Sub(large) }, assertion: outOfRange},
+		{name: "mul", op: func() (Rational, error) { return tiny.Mul(tiny) }, assertion: outOfRange},
+		{name: "neg", op: Rational{i: math.MinInt64}.Neg, assertion: outOfRange},
+		{
+			name:      "mixed denominators",
+			op:        func() (Rational, error) { return tiny.Add(newFraction(1, math.MaxInt64-1)) },
+			assertion: outOfRange,
+		},
+		{
+			// the working values overflow, but the result fits
+			name:      "fits in lowest terms",
+			op:        func() (Rational, error) { return newFraction(math.MaxInt64, 3).Add(newFraction(math.MaxInt64-2, 3)) },
+			want:      newFraction(2*((math.MaxInt64-1)/3), 1),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "mul cancels",
+			op:        func() (Rational, error) { return large.Mul(tiny) },
+			want:      newFraction(1, 1),
+			assertion: assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// comparisons never overflow
+	assert.Equal(t, 1, large.Cmp(negLarge))
+	assert.Equal(t, -1, tiny.Cmp(newFraction(1, math.MaxInt64-1)))
+}
+
+func outOfRange(t assert.TestingT, err error, msgAndArgs ...interface{}) bool {
+	return assert.ErrorIs(t, err, ErrRange, msgAndArgs...)
+}
+
+func TestRational_ParsedArithmetic(t *testing.T) {
+	a, err := ParseDecimal("-1.2")
+	assert.NoError(t, err)
+
+	b, err := ParseDecimal("-.2")
+	assert.NoError(t, err)
+
+	sum, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, newFraction(-7, 5), sum)
+
+	product, err := a.Mul(b)
+	assert.NoError(t, err)
+	assert.Equal(t, newFraction(6, 25), product)
+}
+
+func TestRational_Mixed(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Rational
+		want string
+	}{
+		{name: "zero", r: Rational{}, want: "0"},
+		{name: "integer", r: newFraction(3, 1), want: "3"},
+		{name: "proper", r: newFraction(3, 4), want: "3/4"},
+		{name: "mixed", r: newFraction(3, 2), want: "1 1/2"},
+		{name: "negative proper", r: newFraction(-3, 4), want: "-3/4"},
+		{name: "negative mixed", r: newFraction(-6, 5), want: "-1 1/5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.r.Mixed())
+		})
+	}
+}
+
+func TestNewRational(t *testing.T) {
+	tests := []struct {
+		name      string
+		i, n, d   int64
+		want      Rational
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "whole", i: 3, want: newFraction(3, 1), assertion: assert.NoError},
+		{name: "mixed", i: 1, n: 1, d: 2, want: newFraction(3, 2), assertion: assert.NoError},
+		{name: "reduced", i: 2, n: 4, d: 8, want: newFraction(5, 2), assertion: assert.NoError},
+		{name: "negative mixed", i: -1, n: 1, d: 2, want: newFraction(-3, 2), assertion: assert.NoError},
+		{name: "negative proper", n: -3, d: 4, want: newFraction(-3, 4), assertion: assert.NoError},
+		{name: "zero denominator", n: 1, d: 0, assertion: assert.Error},
+		{name: "negative denominator", n: 1, d: -2, assertion: assert.Error},
+		{name: "improper", i: 1, n: 3, d: 2, assertion: assert.Error},
+		{name: "two signs", i: -1, n: -1, d: 2, assertion: assert.Error},
+		{name: "overflow", i: 1 << 62, n: 1, d: 4, assertion: assert.Error},
+		{name: "negative overflow", i: -1 << 62, n: 1, d: 4, assertion: assert.Error},
+		{name: "min int64", i: math.MinInt64, n: 1, d: 2, assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRational(tt.i, tt.n, tt.d)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRational(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      Rational
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "whole", arg: "2", want: newFraction(2, 1), assertion: assert.NoError},
+		{name: "mixed", arg: " 1 1/2 ", want: newFraction(3, 2), assertion: assert.NoError},
+		{name: "string form", arg: "1 + 1/2", want: newFraction(3, 2), assertion: assert.NoError},
+		{name: "proper", arg: "6/8", want: newFraction(3, 4), assertion: assert.NoError},
+		{name: "negative mixed", arg: "-1 1/5", want: newFraction(-6, 5), assertion: assert.NoError},
+		{name: "negative proper", arg: "-3/4", want: newFraction(-3, 4), assertion: assert.NoError},
+		{name: "decimal", arg: "1.5", want: newFraction(3, 2), assertion: assert.NoError},
+		{name: "empty", arg: "", assertion: assert.Error},
+		{name: "words", arg: "one half", assertion: assert.Error},
+		{name: "zero denominator", arg: "1/0", assertion: assert.Error},
+		{name: "improper mixed", arg: "1 3/2", assertion: assert.Error},
+		{name: "too many parts", arg: "1 1/2 3", assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRational(tt.arg)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRational_RoundTrip(t *testing.T) {
+	values := []Rational{{}, newFraction(3, 1), newFraction(3, 2), newFraction(-6, 5), newFraction(-1, 16)}
+
+	for _, v := range values {
+		text, err := v.MarshalText()
+		assert.NoError(t, err)
+
+		var fromText Rational
+		assert.NoError(t, fromText.UnmarshalText(text))
+		assert.Equal(t, v, fromText)
+
+		data, err := json.Marshal(v)
+		assert.NoError(t, err)
+
+		var fromJSON Rational
+		assert.NoError(t, json.Unmarshal(data, &fromJSON))
+		assert.Equal(t, v, fromJSON)
+
+		data, err = yaml.Marshal(v)
+		assert.NoError(t, err)
+
+		var fromYAML Rational
+		assert.NoError(t, yaml.Unmarshal(data, &fromYAML))
+		assert.Equal(t, v, fromYAML)
+	}
+}
+
+func TestRational_Unmarshal(t *testing.T) {
+	var preset struct {
+		Margin Rational `json:"margin" yaml:"margin"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"margin": 0.4375}`), &preset))
+	assert.Equal(t, newFraction(7, 16), preset.Margin)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"margin": "1 1/2"}`), &preset))
+	assert.Equal(t, newFraction(3, 2), preset.Margin)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"margin": true}`), &preset))
+
+	assert.NoError(t, yaml.Unmarshal([]byte("margin: 0.4375\n"), &preset))
+	assert.Equal(t, newFraction(7, 16), preset.Margin)
+
+	assert.NoError(t, yaml.Unmarshal([]byte("margin: 1 1/2\n"), &preset))
+	assert.Equal(t, newFraction(3, 2), preset.Margin)
+
+	assert.Error(t, yaml.Unmarshal([]byte("margin: [1, 2]\n"), &preset))
+
+	data, err := json.Marshal(preset)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"margin": "1 1/2"}`, string(data))
+}
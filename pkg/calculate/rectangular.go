@@ -0,0 +1,125 @@
+package calculate
+
+import "math"
+
+// EdgePunch is a punch position on one edge of a sheet.
+type EdgePunch struct {
+	Edge     string  `json:"edge"`     // sheet edge: top, right, bottom or left
+	Position float64 `json:"position"` // distance from the left end of the top and bottom edges, or the top end of the side edges
+
+	// Placeable reports whether the board guide reaches the punch, measuring
+	// from the start of the edge or, if FromEnd is set, from its far end.
+	Placeable bool `json:"placeable"`
+	FromEnd   bool `json:"from_end,omitempty"`
+}
+
+// RectangularEnvelope is the result of a rectangular paper envelope calculation.
+//
+// The content is turned less than 45° to the paper edges, which makes the
+// sheet narrower than the square layout. Its corners are still a margin from
+// each edge and are punched on the board, but the folds between them are no
+// longer at the 45° of the board's scoring groove and are scored with a ruler.
+type RectangularEnvelope struct {
+	Spec   EnvelopeSpec `json:"spec"`
+	Stack  *Stack       `json:"stack,omitempty"` // stacked content, if the spec has items
+	Margin float64      `json:"margin"`
+	Angle  float64      `json:"angle"` // angle between the long content edge and the long paper edge, in degrees
+	Paper  Size         `json:"paper"` // length along the top edge, width along the side edges
+
+	Punches      []EdgePunch `json:"punches"`       // one punch per edge, clockwise from the top
+	ClosingFlap  float64     `json:"closing_flap"`  // depth of the flaps on the long content edges
+	SideFlap     float64     `json:"side_flap"`     // depth of the flaps on the short content edges
+	SquareSize   float64     `json:"square_size"`   // side of the square paper for the same content
+	PaperSaved   float64     `json:"paper_saved"`   // area saved compared with the square paper
+	PercentSaved float64     `json:"percent_saved"` // area saved as a percentage of the square paper
+}
+
+// angleTolerance is the precision, in radians, of the content angle.
+const angleTolerance = 1e-12
+
+// CalculateRectangular calculates the rectangular paper and punch positions
+// for an envelope.
+//
+// The content is turned as little as possible from the paper edges while the
+// closing flaps still overlap by twice the margin, so that each reaches a
+// margin past the middle of the content.
+func (s EnvelopeSpec) CalculateRectangular() (RectangularEnvelope, error) {
+	s, stack, err := s.withStack()
+	if err != nil {
+		return RectangularEnvelope{}, err
+	}
+
+	if err := s.Validate(); err != nil {
+		return RectangularEnvelope{}, err
+	}
+
+	m, _, _ := s.margin()
+	long, short := math.Max(s.Length, s.Width), math.Min(s.Length, s.Width)
+
+	// twice the closing flap depth at angle t
+	closing := func(t float64) float64 {
+		return long*math.Sin(2*t) + 2*m*(math.Sin(t)+math.Cos(t))
+	}
+
+	// the flaps grow with the angle, so find the smallest one that overlaps
+	lo, hi := 0.0, math.Pi/4
+	for hi-lo > angleTolerance {
+		mid := (lo + hi) / 2
+		if closing(mid) >= short+2*m {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	t := hi
+	sin, cos := math.Sin(t), math.Cos(t)
+
+	env := RectangularEnvelope{
+		Spec:        s,
+		Stack:       stack,
+		Margin:      m,
+		Angle:       t * 180 / math.Pi,
+		Paper:       Size{Length: long*cos + short*sin + 2*m, Width: long*sin + short*cos + 2*m},
+		ClosingFlap: closing(t) / 2,
+		SideFlap:    (short*math.Sin(2*t) + 2*m*(sin+cos)) / 2,
+		SquareSize:  (long+short)*math.Sqrt(0.5) + 2*m,
+	}
+
+	if err := checkBoardLimit(env.Paper, s.Unit, s.Board); err != nil {
+		return RectangularEnvelope{}, err
+	}
+
+	p, q := env.Paper.Length, env.Paper.Width
+	across := (long*cos - short*sin) / 2 // offset of the top and bottom corners from the middle
+	down := (long*sin - short*cos) / 2   // offset of the side corners from the middle
+
+	guide := MaxPaperSize(s.Unit, s.Board)
+	env.Punches = []EdgePunch{
+		placePunch(EdgeTop, p/2-across, p, guide),
+		placePunch(EdgeRight, q/2+down, q, guide),
+		placePunch(EdgeBottom, p/2+across, p, guide),
+		placePunch(EdgeLeft, q/2-down, q, guide),
+	}
+
+	env.PaperSaved = env.SquareSize*env.SquareSize - p*q
+	env.PercentSaved = 100 * env.PaperSaved / (env.SquareSize * env.SquareSize)
+
+	return env, nil
+}
+
+// placePunch returns a punch at pos along an edge of length edge, and whether
+// a board guide of length guide reaches it from either end of the edge.
+func placePunch(e string, pos, edge, guide float64) EdgePunch {
+	p := EdgePunch{Edge: e, Position: pos}
+
+	switch {
+	case pos <= guide:
+		p.Placeable = true
+	case edge-pos <= guide:
+		p.Placeable = true
+		p.FromEnd = true
+	}
+
+	return p
+}
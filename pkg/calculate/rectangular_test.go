@@ -0,0 +1,99 @@
+package calculate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeSpec_CalculateRectangular(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    EnvelopeSpec
+		angle   float64
+		paper   Size
+		punches []EdgePunch
+		saved   float64
+	}{
+		{
+			name:  "DL",
+			spec:  EnvelopeSpec{Length: 22, Width: 11},
+			angle: 14.291,
+			paper: Size{Length: 26.234, Width: 18.290},
+			punches: []EdgePunch{
+				{Edge: EdgeTop, Position: 3.815, Placeable: true},
+				{Edge: EdgeRight, Position: 6.531, Placeable: true},
+				{Edge: EdgeBottom, Position: 22.419, Placeable: true},
+				{Edge: EdgeLeft, Position: 11.760, Placeable: true},
+			},
+			saved: 26.407,
+		},
+		{
+			name:  "width longer than length",
+			spec:  EnvelopeSpec{Length: 11, Width: 22},
+			angle: 14.291,
+			paper: Size{Length: 26.234, Width: 18.290},
+			punches: []EdgePunch{
+				{Edge: EdgeTop, Position: 3.815, Placeable: true},
+				{Edge: EdgeRight, Position: 6.531, Placeable: true},
+				{Edge: EdgeBottom, Position: 22.419, Placeable: true},
+				{Edge: EdgeLeft, Position: 11.760, Placeable: true},
+			},
+			saved: 26.407,
+		},
+		{
+			name:  "guide reaches from the far end",
+			spec:  EnvelopeSpec{Length: 9.25, Width: 4.25, Unit: UnitImperial, Board: BoardMini},
+			angle: 13.322,
+			paper: Size{Length: 10.480, Width: 6.767},
+			punches: []EdgePunch{
+				{Edge: EdgeTop, Position: 1.229, Placeable: true},
+				{Edge: EdgeRight, Position: 2.381, Placeable: true},
+				{Edge: EdgeBottom, Position: 9.251, Placeable: true, FromEnd: true},
+				{Edge: EdgeLeft, Position: 4.386, Placeable: true},
+			},
+			saved: 29.725,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const delta = 0.001
+
+			got, err := tt.spec.CalculateRectangular()
+			require.NoError(t, err)
+
+			assert.InDelta(t, tt.angle, got.Angle, delta)
+			assert.InDelta(t, tt.paper.Length, got.Paper.Length, delta)
+			assert.InDelta(t, tt.paper.Width, got.Paper.Width, delta)
+			assert.InDelta(t, tt.saved, got.PercentSaved, delta)
+
+			require.Len(t, got.Punches, len(tt.punches))
+
+			for i, p := range got.Punches {
+				assert.Equal(t, tt.punches[i].Edge, p.Edge)
+				assert.InDelta(t, tt.punches[i].Position, p.Position, delta, p.Edge)
+				assert.Equal(t, tt.punches[i].Placeable, p.Placeable, p.Edge)
+				assert.Equal(t, tt.punches[i].FromEnd, p.FromEnd, p.Edge)
+			}
+
+			// the closing flaps overlap by twice the margin
+			assert.InDelta(t, math.Min(tt.spec.Length, tt.spec.Width)+2*got.Margin, 2*got.ClosingFlap, 1e-9)
+		})
+	}
+}
+
+func TestEnvelopeSpec_CalculateRectangularErrors(t *testing.T) {
+	_, err := EnvelopeSpec{Length: 22}.CalculateRectangular()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+
+	_, err = EnvelopeSpec{Length: 30, Width: 28}.CalculateRectangular()
+	assert.ErrorIs(t, err, ErrBoardLimit)
+}
+
+func TestPlacePunch(t *testing.T) {
+	assert.Equal(t, EdgePunch{Edge: EdgeTop, Position: 5, Placeable: true}, placePunch(EdgeTop, 5, 30, 12))
+	assert.Equal(t, EdgePunch{Edge: EdgeTop, Position: 20, Placeable: true, FromEnd: true}, placePunch(EdgeTop, 20, 30, 12))
+	assert.Equal(t, EdgePunch{Edge: EdgeTop, Position: 15}, placePunch(EdgeTop, 15, 30, 12))
+}
@@ -0,0 +1,89 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ContentItem is one card or sheet of the content of an envelope or box.
+type ContentItem struct {
+	Length    float64 `json:"length"`
+	Width     float64 `json:"width"`
+	Thickness float64 `json:"thickness,omitempty"`
+}
+
+// Stack is a pile of content items with their long sides together.
+type Stack struct {
+	Count     int     `json:"count"`     // number of items
+	Length    float64 `json:"length"`    // longest side of any item
+	Width     float64 `json:"width"`     // longest short side of any item
+	Thickness float64 `json:"thickness"` // total thickness of the items
+}
+
+// ParseContentItem parses an item such as "7x5", "7 x 5 in" or
+// "18x13cmx0.5mm": length, width and an optional thickness. A unit written
+// once applies to every side without its own, as in parseSides, and the item
+// is measured in def.
+func ParseContentItem(s string, def LengthUnit) (ContentItem, error) {
+	parts := strings.Split(strings.ReplaceAll(strings.ToLower(s), "×", "x"), "x")
+	if len(parts) < 2 || len(parts) > 3 {
+		return ContentItem{}, fmt.Errorf("%w: item %q: expected length x width or length x width x thickness", ErrSyntax, s)
+	}
+
+	measured, err := parseSides(parts, def)
+	if err != nil {
+		return ContentItem{}, err
+	}
+
+	sides := make([]float64, 3)
+	for i, m := range measured {
+		sides[i] = roundLength(m.To(def).Float64())
+	}
+
+	item := ContentItem{Length: sides[0], Width: sides[1], Thickness: sides[2]}
+	if item.Length <= 0 || item.Width <= 0 || item.Thickness < 0 {
+		return ContentItem{}, fmt.Errorf("%w: item %q must have a positive length and width", ErrInvalidDimension, s)
+	}
+
+	return item, nil
+}
+
+// NewStack returns the stack of the given items.
+func NewStack(items []ContentItem) (Stack, error) {
+	if len(items) == 0 {
+		return Stack{}, fmt.Errorf("%w: a stack needs at least one item", ErrInvalidDimension)
+	}
+
+	st := Stack{Count: len(items)}
+
+	for i, it := range items {
+		if it.Length <= 0 || it.Width <= 0 || it.Thickness < 0 {
+			return Stack{}, fmt.Errorf("%w: item %d must have a positive length and width, got %g x %g x %g",
+				ErrInvalidDimension, i+1, it.Length, it.Width, it.Thickness)
+		}
+
+		st.Length = math.Max(st.Length, math.Max(it.Length, it.Width))
+		st.Width = math.Max(st.Width, math.Min(it.Length, it.Width))
+		st.Thickness += it.Thickness
+	}
+
+	st.Thickness = roundLength(st.Thickness)
+
+	return st, nil
+}
+
+// EnvelopeContent returns the content size an envelope needs for the stack.
+// The flaps wrap round the edges of the stack, so each side grows by its thickness.
+func (st Stack) EnvelopeContent() (length, width float64) {
+	return roundLength(st.Length + st.Thickness), roundLength(st.Width + st.Thickness)
+}
+
+// lengthScale is the finest step content lengths are kept to, a millionth of
+// the unit, so that they have a short exact decimal form.
+const lengthScale = 1e6
+
+// roundLength rounds a length to the nearest millionth of its unit.
+func roundLength(f float64) float64 {
+	return math.Round(f*lengthScale) / lengthScale
+}
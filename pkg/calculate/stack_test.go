@@ -0,0 +1,155 @@
+package calculate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContentItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    ContentItem
+		wantErr error
+	}{
+		{name: "length and width", input: "7x5", want: ContentItem{Length: 7, Width: 5}},
+		{name: "spaces and unit", input: "7 x 5 in", want: ContentItem{Length: 7, Width: 5}},
+		{name: "thickness", input: "5×3.5×0.1", want: ContentItem{Length: 5, Width: 3.5, Thickness: 0.1}},
+		{name: "mixed units", input: "12.7x8.89cmx2.54mm", want: ContentItem{Length: 5, Width: 3.5, Thickness: 0.1}},
+		{name: "centimetres", input: "12.7cmx12.7", want: ContentItem{Length: 5, Width: 5}},
+		{name: "one side", input: "7", wantErr: ErrSyntax},
+		{name: "four sides", input: "7x5x1x1", wantErr: ErrSyntax},
+		{name: "not a number", input: "7xfive", wantErr: ErrSyntax},
+		{name: "zero width", input: "7x0", wantErr: ErrInvalidDimension},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseContentItem(tt.input, Inch)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseContentItem_OneUnit(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  ContentItem
+	}{
+		{name: "trailing unit", input: "7 x 5 in", want: ContentItem{Length: 17.78, Width: 12.7}},
+		{name: "trailing unit without spaces", input: "7x5in", want: ContentItem{Length: 17.78, Width: 12.7}},
+		{name: "leading unit", input: "7in x 5", want: ContentItem{Length: 17.78, Width: 12.7}},
+		{name: "thickness unit", input: "7 x 5 x 0.1 in", want: ContentItem{Length: 17.78, Width: 12.7, Thickness: 0.254}},
+		{name: "own thickness unit", input: "18x13cmx0.5mm", want: ContentItem{Length: 18, Width: 13, Thickness: 0.05}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseContentItem(tt.input, Centimetre)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewStack(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []ContentItem
+		want    Stack
+		wantErr error
+	}{
+		{
+			name:  "one item",
+			items: []ContentItem{{Length: 7, Width: 5}},
+			want:  Stack{Count: 1, Length: 7, Width: 5},
+		},
+		{
+			name:  "turned items",
+			items: []ContentItem{{Length: 5, Width: 7, Thickness: 0.1}, {Length: 3.5, Width: 5, Thickness: 0.2}, {Length: 6.5, Width: 2}},
+			want:  Stack{Count: 3, Length: 7, Width: 5, Thickness: 0.3},
+		},
+		{
+			name:  "widest is not longest",
+			items: []ContentItem{{Length: 8, Width: 2}, {Length: 5, Width: 5}},
+			want:  Stack{Count: 2, Length: 8, Width: 5},
+		},
+		{
+			name:    "no items",
+			wantErr: ErrInvalidDimension,
+		},
+		{
+			name:    "negative thickness",
+			items:   []ContentItem{{Length: 7, Width: 5, Thickness: -1}},
+			wantErr: ErrInvalidDimension,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewStack(tt.items)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnvelopeSpec_CalculateItems(t *testing.T) {
+	items := []ContentItem{{Length: 7, Width: 5, Thickness: 0.1}, {Length: 5, Width: 3.5, Thickness: 0.2}}
+
+	env, err := EnvelopeSpec{Unit: UnitImperial, Items: items}.Calculate()
+	require.NoError(t, err)
+	require.NotNil(t, env.Stack)
+	assert.Equal(t, Stack{Count: 2, Length: 7, Width: 5, Thickness: 0.3}, *env.Stack)
+	assert.Equal(t, 7.3, env.Spec.Length)
+	assert.Equal(t, 5.3, env.Spec.Width)
+
+	want, err := EnvelopeSpec{Length: 7.3, Width: 5.3, Unit: UnitImperial}.Calculate()
+	require.NoError(t, err)
+	assert.Equal(t, want.PaperSize, env.PaperSize)
+
+	exact, err := EnvelopeSpec{Unit: UnitImperial, Items: items}.CalculateExact()
+	require.NoError(t, err)
+	assert.Equal(t, env.Stack, exact.Envelope().Stack)
+	assert.InDelta(t, env.PaperSize, exact.Envelope().PaperSize, 1e-9)
+
+	rect, err := EnvelopeSpec{Unit: UnitImperial, Items: items}.CalculateRectangular()
+	require.NoError(t, err)
+	assert.Equal(t, env.Stack, rect.Stack)
+
+	_, err = EnvelopeSpec{Unit: UnitImperial, Items: []ContentItem{{Length: 7}}}.Calculate()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+}
+
+func TestBoxSpec_CalculateItems(t *testing.T) {
+	items := []ContentItem{{Length: 4, Width: 3, Thickness: 0.5}, {Length: 3, Width: 3, Thickness: 0.5}}
+
+	box, err := BoxSpec{Unit: UnitImperial, Items: items}.Calculate()
+	require.NoError(t, err)
+	require.NotNil(t, box.Stack)
+	assert.Equal(t, 1.0, box.Base.Height)
+	assert.Equal(t, 4.0, box.Base.Length)
+	assert.Equal(t, 3.0, box.Base.Width)
+
+	box, err = BoxSpec{Unit: UnitImperial, Items: items, Height: 2}.Calculate()
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, box.Base.Height)
+
+	_, err = BoxSpec{Unit: UnitImperial, Items: []ContentItem{{Length: 4, Width: 3}}}.Calculate()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+}
@@ -0,0 +1,101 @@
+package calculate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StockFit is an envelope laid out on the smallest stock size that holds it.
+// The content stays centred, so the margin grows by the same amount on every side.
+type StockFit struct {
+	PaperSize     float64 `json:"paper_size"`     // side of the stock sheet
+	PunchLocation float64 `json:"punch_location"` // first punch position along the stock sheet edge
+	ExtraMargin   float64 `json:"extra_margin"`   // added to the margin on every side
+}
+
+// standardStockSizes lists the square stock sizes used by "standard", in inches and centimetres.
+var standardStockSizes = map[Unit][]float64{
+	UnitImperial: {6, 6.5, 8.5, 12},
+	UnitMetric:   {15, 20, 30.5},
+}
+
+// StandardStockSizes returns the common square paper sizes in unit, smallest first.
+func StandardStockSizes(unit Unit) []float64 {
+	return append([]float64(nil), standardStockSizes[unit]...)
+}
+
+// ParseStockSizes parses a list of square paper sizes separated by commas or
+// spaces, such as "6, 6.5, 8.5, 12" or "6in 30.5cm", and returns them in unit,
+// smallest first. Sizes without a unit are measured in unit. "standard" gives
+// StandardStockSizes, and "none" or an empty string gives no sizes.
+func ParseStockSizes(s string, unit Unit) ([]float64, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+
+	switch text {
+	case "", "none":
+		return nil, nil
+	case "standard":
+		return StandardStockSizes(unit), nil
+	}
+
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' })
+	sizes := make([]float64, 0, len(fields))
+
+	for _, f := range fields {
+		m, err := ParseMeasurement(f, unit.Length())
+		if err != nil {
+			return nil, err
+		}
+
+		size := m.To(unit.Length()).Float64()
+		if size <= 0 {
+			return nil, fmt.Errorf("%w: stock size %q must be greater than zero", ErrInvalidDimension, f)
+		}
+
+		sizes = append(sizes, size)
+	}
+
+	sort.Float64s(sizes)
+
+	return sizes, nil
+}
+
+// Sheet returns the side of the paper the envelope is cut from, its punch
+// location and its margin. These are the stock sheet's when the paper is
+// rounded up to a stock size, and otherwise the paper square's.
+func (e Envelope) Sheet() (paper, punch, margin float64) {
+	if e.Stock != nil {
+		return e.Stock.PaperSize, e.Stock.PunchLocation, e.Margin + e.Stock.ExtraMargin
+	}
+
+	return e.PaperSize, e.PunchLocation, e.Margin
+}
+
+// newStockFit returns the envelope rounded up to the smallest of the spec's
+// stock sizes that holds it, or nil if the spec has no stock sizes.
+func newStockFit(e Envelope) (*StockFit, error) {
+	sizes := e.Spec.StockSizes
+	if len(sizes) == 0 {
+		return nil, nil
+	}
+
+	i := sort.Search(len(sizes), func(i int) bool { return sizes[i] >= e.PaperSize-fitTolerance })
+	if i == len(sizes) {
+		return nil, fmt.Errorf("%w: paper size %0.2f %s is larger than every stock size, the largest is %g %s",
+			ErrInvalidDimension, e.PaperSize, e.Spec.Unit, sizes[len(sizes)-1], e.Spec.Unit)
+	}
+
+	size := sizes[i]
+	if size > MaxPaperSize(e.Spec.Unit, e.Spec.Board) {
+		return nil, e.Spec.boardLimitError(size)
+	}
+
+	// the paper grows by two margins
+	extra := (size - e.PaperSize) / 2
+	if extra < 0 {
+		extra = 0
+	}
+
+	return &StockFit{PaperSize: size, PunchLocation: e.PunchLocation + extra, ExtraMargin: extra}, nil
+}
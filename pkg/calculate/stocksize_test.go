@@ -0,0 +1,116 @@
+package calculate
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStockSizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		unit    Unit
+		want    []float64
+		wantErr error
+	}{
+		{name: "none", input: "none", unit: UnitImperial, want: nil},
+		{name: "empty", input: " ", unit: UnitImperial, want: nil},
+		{name: "standard inches", input: "standard", unit: UnitImperial, want: []float64{6, 6.5, 8.5, 12}},
+		{name: "standard centimetres", input: "Standard", unit: UnitMetric, want: []float64{15, 20, 30.5}},
+		{name: "sorted", input: "12, 6 8.5", unit: UnitImperial, want: []float64{6, 8.5, 12}},
+		{name: "mixed units", input: "30.48cm,6in", unit: UnitImperial, want: []float64{6, 12}},
+		{name: "not a size", input: "6, eight", unit: UnitImperial, wantErr: ErrSyntax},
+		{name: "zero", input: "0, 6", unit: UnitImperial, wantErr: ErrInvalidDimension},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStockSizes(tt.input, tt.unit)
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnvelopeSpec_CalculateStock(t *testing.T) {
+	tests := []struct {
+		name      string
+		sizes     []float64
+		wantSize  float64
+		wantExtra float64
+		wantErr   error
+	}{
+		{name: "no stock sizes"},
+		{name: "rounded up", sizes: []float64{6, 8.5, 10, 12}, wantSize: 10, wantExtra: 0.3199},
+		{name: "larger than every size", sizes: []float64{6, 8.5}, wantErr: ErrInvalidDimension},
+		{name: "larger than the board", sizes: []float64{6, 13}, wantErr: ErrBoardLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := EnvelopeSpec{Length: 7, Width: 5, Unit: UnitImperial, StockSizes: tt.sizes}
+
+			env, err := spec.Calculate()
+			_, exactErr := spec.CalculateExact()
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, exactErr, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, exactErr)
+
+			if tt.sizes == nil {
+				assert.Nil(t, env.Stock)
+				return
+			}
+
+			require.NotNil(t, env.Stock)
+			assert.Equal(t, tt.wantSize, env.Stock.PaperSize)
+			assert.InDelta(t, tt.wantExtra, env.Stock.ExtraMargin, 0.0001)
+			assert.InDelta(t, env.PunchLocation+env.Stock.ExtraMargin, env.Stock.PunchLocation, 1e-9)
+
+			// the stock sheet is what the envelope would need with the larger margin
+			assert.InDelta(t, env.Stock.PaperSize, env.LengthProjection+env.WidthProjection+2*(env.Margin+env.Stock.ExtraMargin), 1e-9)
+		})
+	}
+}
+
+func TestEnvelopeSpec_CalculateStockSheet(t *testing.T) {
+	spec := EnvelopeSpec{Length: 10, Width: 8, StockSizes: []float64{20}, LinerInset: 1, CheckFlaps: true, Steps: true}
+
+	env, err := spec.Calculate()
+	require.NoError(t, err)
+	require.NotNil(t, env.Stock)
+
+	paper, punch, margin := env.Sheet()
+	assert.Equal(t, 20.0, paper)
+	assert.Equal(t, env.Stock.PunchLocation, punch)
+	assert.InDelta(t, env.Margin+env.Stock.ExtraMargin, margin, 1e-9)
+
+	// every section is cut from the stock sheet, not the smaller paper square
+	a := math.Min(env.LengthProjection, env.WidthProjection)
+	b := math.Max(env.LengthProjection, env.WidthProjection)
+
+	assert.InDelta(t, 18, env.Liner.Size, 1e-9)
+	assert.InDelta(t, (paper-a)*math.Sqrt(0.5), env.Flaps.TopReach, 1e-9)
+	assert.InDelta(t, (paper-b)*math.Sqrt(0.5), env.Flaps.SideReach, 1e-9)
+	assert.Equal(t, punchBoardSteps(punch), env.Instructions.PunchBoard)
+
+	exact, err := spec.CalculateExact()
+	require.NoError(t, err)
+
+	exactEnv := exact.Envelope()
+	assert.InDelta(t, env.Liner.Size, exactEnv.Liner.Size, 1e-9)
+	assert.InDelta(t, env.Flaps.TopReach, exactEnv.Flaps.TopReach, 1e-9)
+}
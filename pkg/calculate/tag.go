@@ -0,0 +1,72 @@
+package calculate
+
+import "fmt"
+
+// TagSpec describes a gift tag.
+type TagSpec struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Corner bool    `json:"corner"` // punch off both top corners
+	Unit   Unit    `json:"unit"`
+}
+
+// Tag is the result of a tag calculation.
+type Tag struct {
+	Spec       TagSpec `json:"spec"`
+	Size       Size    `json:"size"`        // length across the top, width from top to bottom
+	CornerCut  float64 `json:"corner_cut"`  // distance along each edge cut off the top corners; zero for square corners
+	HoleOffset float64 `json:"hole_offset"` // distance from the top edge to the centre of the string hole
+}
+
+// tagSizes returns the corner cut and hole offset for a unit.
+func tagSizes(unit Unit) (corner, hole float64) {
+	if unit == UnitImperial {
+		return 0.5, 0.375
+	}
+
+	return 1.2, 1
+}
+
+// Validate reports whether the spec can be used for a calculation.
+func (s TagSpec) Validate() error {
+	if s.Width <= 0 {
+		return fmt.Errorf("%w: width must be greater than zero, got %g", ErrInvalidDimension, s.Width)
+	}
+
+	if s.Height <= 0 {
+		return fmt.Errorf("%w: height must be greater than zero, got %g", ErrInvalidDimension, s.Height)
+	}
+
+	corner, hole := tagSizes(s.Unit)
+
+	if s.Height < 2*hole {
+		return fmt.Errorf("%w: height %g leaves no room below the string hole", ErrInvalidDimension, s.Height)
+	}
+
+	if s.Corner && (s.Width <= 2*corner || s.Height <= corner) {
+		return fmt.Errorf("%w: %g x %g %s tag is too small for punched corners", ErrInvalidDimension, s.Width, s.Height, s.Unit)
+	}
+
+	return nil
+}
+
+// Calculate calculates the corner cuts and hole position for a tag. The hole is centred across the tag.
+func (s TagSpec) Calculate() (Tag, error) {
+	if err := s.Validate(); err != nil {
+		return Tag{}, err
+	}
+
+	corner, hole := tagSizes(s.Unit)
+
+	tag := Tag{
+		Spec:       s,
+		Size:       Size{Length: s.Width, Width: s.Height},
+		HoleOffset: hole,
+	}
+
+	if s.Corner {
+		tag.CornerCut = corner
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,58 @@
+package calculate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagSpec_Calculate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    TagSpec
+		want    Tag
+		wantErr error
+	}{
+		{
+			name: "square corners",
+			spec: TagSpec{Width: 5, Height: 8},
+			want: Tag{Size: Size{Length: 5, Width: 8}, HoleOffset: 1},
+		},
+		{
+			name: "punched corners",
+			spec: TagSpec{Width: 2, Height: 3.5, Corner: true, Unit: UnitImperial},
+			want: Tag{Size: Size{Length: 2, Width: 3.5}, CornerCut: 0.5, HoleOffset: 0.375},
+		},
+		{
+			name:    "zero width",
+			spec:    TagSpec{Height: 8},
+			wantErr: errors.New("invalid dimension: width must be greater than zero, got 0"),
+		},
+		{
+			name:    "too short for the hole",
+			spec:    TagSpec{Width: 5, Height: 1.5},
+			wantErr: ErrInvalidDimension,
+		},
+		{
+			name:    "too narrow for punched corners",
+			spec:    TagSpec{Width: 2.4, Height: 8, Corner: true},
+			wantErr: ErrInvalidDimension,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Calculate()
+
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr) || err.Error() == tt.wantErr.Error(), err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			tt.want.Spec = tt.spec
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package calculate
+
+import (
+	"fmt"
+	"math"
+)
+
+// Tolerance is how far each measurement of an envelope can be out, either way.
+type Tolerance struct {
+	Content float64 `json:"content,omitempty"` // content length and width, as measured
+	Cut     float64 `json:"cut,omitempty"`     // paper size, as cut
+	Punch   float64 `json:"punch,omitempty"`   // punch position along the paper edge
+}
+
+// Validate reports whether the tolerance can be used for a sensitivity report.
+func (t Tolerance) Validate() error {
+	switch {
+	case t.Content < 0:
+		return fmt.Errorf("%w: content tolerance must not be negative, got %g", ErrInvalidDimension, t.Content)
+	case t.Cut < 0:
+		return fmt.Errorf("%w: cut tolerance must not be negative, got %g", ErrInvalidDimension, t.Cut)
+	case t.Punch < 0:
+		return fmt.Errorf("%w: punch tolerance must not be negative, got %g", ErrInvalidDimension, t.Punch)
+	}
+
+	return nil
+}
+
+// Range is the smallest and largest value a result can take within a tolerance.
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// spread returns the range of f plus or minus d.
+func spread(f, d float64) Range {
+	return Range{Min: f - d, Max: f + d}
+}
+
+// Sensitivity reports how far the results of an envelope calculation can move
+// when the measurements are only accurate to a tolerance.
+type Sensitivity struct {
+	Tolerance     Tolerance `json:"tolerance"`
+	PaperSize     Range     `json:"paper_size"`     // paper the content needs, as cut
+	PunchLocation Range     `json:"punch_location"` // punch location the content needs, as placed
+	Margin        Range     `json:"margin"`         // distance from a content corner to the paper edge
+
+	SideOverlap       Range `json:"side_overlap"`
+	TopBottomOverlap  Range `json:"top_bottom_overlap"`
+	SideBottomOverlap Range `json:"side_bottom_overlap"`
+	TopSideOverlap    Range `json:"top_side_overlap"`
+
+	Fits     bool     `json:"fits"`               // the content fits and the flaps cover it however the errors fall
+	Warnings []string `json:"warnings,omitempty"` // ways the content could fail to fit
+}
+
+// newSensitivity returns the sensitivity report for an envelope, or nil if the
+// spec has no tolerance. It is for the stock sheet if there is one.
+//
+// A content error moves each projection, and so each content corner, by the
+// error over root two; a cut error moves each paper edge by half of it; and a
+// punch error moves the folds, and the content corners with them, along the
+// paper edge by up to the error. The flaps are deepest with the content small,
+// the paper large and the folds pushed away from the paper corners, and
+// shallowest the other way round.
+func newSensitivity(e Envelope) *Sensitivity {
+	tol := e.Spec.Tolerance
+	if tol == nil {
+		return nil
+	}
+
+	paper, punch, margin := e.Sheet()
+
+	content := tol.Content * math.Sqrt(0.5)
+
+	s := &Sensitivity{
+		Tolerance:     *tol,
+		PaperSize:     spread(paper, 2*content+tol.Cut),
+		PunchLocation: spread(punch, content+tol.Punch),
+		Margin:        spread(margin, content+tol.Cut/2+tol.Punch),
+	}
+
+	worst := sensitivityFlaps(e, paper, content, -tol.Cut, -tol.Punch)
+	best := sensitivityFlaps(e, paper, -content, tol.Cut, tol.Punch)
+
+	s.SideOverlap = Range{Min: worst.SideOverlap, Max: best.SideOverlap}
+	s.TopBottomOverlap = Range{Min: worst.TopBottomOverlap, Max: best.TopBottomOverlap}
+	s.SideBottomOverlap = Range{Min: worst.SideBottomOverlap, Max: best.SideBottomOverlap}
+	s.TopSideOverlap = Range{Min: worst.TopSideOverlap, Max: best.TopSideOverlap}
+
+	if s.Margin.Min < 0 {
+		s.Warnings = append(s.Warnings, fmt.Sprintf("content corners could reach %.3g past the paper edge", -s.Margin.Min))
+	}
+
+	for _, p := range worst.Problems {
+		s.Warnings = append(s.Warnings, "at worst, "+p)
+	}
+
+	s.Fits = len(s.Warnings) == 0
+
+	return s
+}
+
+// sensitivityFlaps returns the flap analysis for an envelope with each
+// projection grown by content, the paper grown by cut and each fold moved
+// away from its paper corner by punch along the paper edge.
+func sensitivityFlaps(e Envelope, paper, content, cut, punch float64) *FlapAnalysis {
+	a := math.Min(e.LengthProjection, e.WidthProjection) + content
+	b := math.Max(e.LengthProjection, e.WidthProjection) + content
+
+	long, short := b*math.Sqrt2, a*math.Sqrt2
+	closing := (paper + cut - a + punch) * math.Sqrt(0.5)
+	side := (paper + cut - b + punch) * math.Sqrt(0.5)
+
+	return analyzeFlaps(long, short, closing, closing, side)
+}
@@ -0,0 +1,142 @@
+package calculate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeSpec_CalculateSensitivity(t *testing.T) {
+	tests := []struct {
+		name       string
+		tolerance  *Tolerance
+		wantPaper  Range
+		wantPunch  Range
+		wantMargin Range
+		wantFits   bool
+		wantWarn   []string
+		wantErr    error
+	}{
+		{name: "no tolerance"},
+		{
+			name:       "a millimetre either way",
+			tolerance:  &Tolerance{Content: 0.04, Cut: 0.04, Punch: 0.04},
+			wantPaper:  Range{Min: 9.2637, Max: 9.4568},
+			wantPunch:  Range{Min: 3.9047, Max: 4.0413},
+			wantMargin: Range{Min: 0.3492, Max: 0.5258},
+			wantFits:   true,
+		},
+		{
+			name:       "could not fit",
+			tolerance:  &Tolerance{Content: 0.5, Punch: 0.25},
+			wantPaper:  Range{Min: 8.6532, Max: 10.0674},
+			wantPunch:  Range{Min: 3.3695, Max: 4.5766},
+			wantMargin: Range{Min: -0.1661, Max: 1.0411},
+			wantWarn: []string{
+				"content corners could reach 0.166 past the paper edge",
+				"at worst, side and bottom flaps leave a gap of 0.0821",
+				"at worst, top and side flaps leave a gap of 0.0821 under the top flap",
+			},
+		},
+		{name: "negative content", tolerance: &Tolerance{Content: -0.1}, wantErr: ErrInvalidDimension},
+		{name: "negative cut", tolerance: &Tolerance{Cut: -0.1}, wantErr: ErrInvalidDimension},
+		{name: "negative punch", tolerance: &Tolerance{Punch: -0.1}, wantErr: ErrInvalidDimension},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := EnvelopeSpec{Length: 7, Width: 5, Unit: UnitImperial, Tolerance: tt.tolerance}
+
+			env, err := spec.Calculate()
+			exact, exactErr := spec.CalculateExact()
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, exactErr, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, exactErr)
+
+			if tt.tolerance == nil {
+				assert.Nil(t, env.Sensitivity)
+				assert.Nil(t, exact.Envelope().Sensitivity)
+				return
+			}
+
+			s := env.Sensitivity
+			require.NotNil(t, s)
+			assert.Equal(t, *tt.tolerance, s.Tolerance)
+			assert.InDelta(t, tt.wantPaper.Min, s.PaperSize.Min, 0.0001)
+			assert.InDelta(t, tt.wantPaper.Max, s.PaperSize.Max, 0.0001)
+			assert.InDelta(t, tt.wantPunch.Min, s.PunchLocation.Min, 0.0001)
+			assert.InDelta(t, tt.wantPunch.Max, s.PunchLocation.Max, 0.0001)
+			assert.InDelta(t, tt.wantMargin.Min, s.Margin.Min, 0.0001)
+			assert.InDelta(t, tt.wantMargin.Max, s.Margin.Max, 0.0001)
+			assert.Equal(t, tt.wantFits, s.Fits)
+			assert.Equal(t, tt.wantWarn, s.Warnings)
+
+			// every overlap range holds the nominal overlap
+			nominal := newFlapAnalysis(Envelope{
+				Spec:             EnvelopeSpec{CheckFlaps: true},
+				LengthProjection: env.LengthProjection,
+				WidthProjection:  env.WidthProjection,
+				PaperSize:        env.PaperSize,
+			})
+			for _, c := range []struct {
+				r Range
+				f float64
+			}{
+				{s.SideOverlap, nominal.SideOverlap},
+				{s.TopBottomOverlap, nominal.TopBottomOverlap},
+				{s.SideBottomOverlap, nominal.SideBottomOverlap},
+				{s.TopSideOverlap, nominal.TopSideOverlap},
+			} {
+				assert.Less(t, c.r.Min, c.f)
+				assert.Greater(t, c.r.Max, c.f)
+			}
+
+			exactS := exact.Envelope().Sensitivity
+			require.NotNil(t, exactS)
+			assert.InDelta(t, s.PaperSize.Min, exactS.PaperSize.Min, 1e-9)
+			assert.InDelta(t, s.PunchLocation.Max, exactS.PunchLocation.Max, 1e-9)
+		})
+	}
+}
+
+func TestEnvelopeSpec_CalculateSensitivityRange(t *testing.T) {
+	spec := EnvelopeSpec{Length: 18, Width: 13}
+
+	env, err := spec.Calculate()
+	require.NoError(t, err)
+
+	spec.Tolerance = &Tolerance{Content: 0.1, Cut: 0.1, Punch: 0.1}
+
+	got, err := spec.Calculate()
+	require.NoError(t, err)
+
+	s := got.Sensitivity
+	require.NotNil(t, s)
+	assert.InDelta(t, env.PaperSize-0.1*(1+2*0.7071), s.PaperSize.Min, 0.0001)
+	assert.InDelta(t, env.PunchLocation+0.1*1.7071, s.PunchLocation.Max, 0.0001)
+	assert.True(t, s.Fits)
+	assert.Empty(t, s.Warnings)
+
+	spec.Tolerance = &Tolerance{Punch: -1}
+
+	_, err = spec.Calculate()
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+}
+
+func TestEnvelopeSpec_CalculateSensitivityStock(t *testing.T) {
+	tol := Tolerance{Cut: 0.04}
+
+	env, err := EnvelopeSpec{Length: 7, Width: 5, Unit: UnitImperial, StockSizes: []float64{10}, Tolerance: &tol}.Calculate()
+	require.NoError(t, err)
+
+	require.NotNil(t, env.Sensitivity)
+	assert.InDelta(t, 10-0.04, env.Sensitivity.PaperSize.Min, 1e-9)
+	assert.InDelta(t, env.Stock.PunchLocation, env.Sensitivity.PunchLocation.Min, 1e-9)
+	assert.InDelta(t, env.Margin+env.Stock.ExtraMargin-0.02, env.Sensitivity.Margin.Min, 1e-9)
+}
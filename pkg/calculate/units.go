@@ -0,0 +1,243 @@
+package calculate
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// LengthUnit is a unit that lengths can be measured and converted in.
+type LengthUnit int
+
+// Supported length units.
+const (
+	Millimetre LengthUnit = iota
+	Centimetre
+	Inch
+)
+
+func (u LengthUnit) String() string {
+	switch u {
+	case Millimetre:
+		return "mm"
+	case Centimetre:
+		return "cm"
+	case Inch:
+		return "in"
+	default:
+		return fmt.Sprintf("LengthUnit(%d)", int(u))
+	}
+}
+
+// ParseLengthUnit parses a unit name or symbol such as "mm", "centimetres" or `"`.
+func ParseLengthUnit(s string) (LengthUnit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "mm", "millimeter", "millimeters", "millimetre", "millimetres":
+		return Millimetre, nil
+	case "cm", "centimeter", "centimeters", "centimetre", "centimetres":
+		return Centimetre, nil
+	case "in", "inch", "inches", `"`, "''", "″":
+		return Inch, nil
+	default:
+		return Millimetre, fmt.Errorf("unknown unit %q", s)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u LengthUnit) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *LengthUnit) UnmarshalText(text []byte) error {
+	v, err := ParseLengthUnit(string(text))
+	if err != nil {
+		return err
+	}
+
+	*u = v
+
+	return nil
+}
+
+// millimetres returns the exact length of one u in millimetres.
+func (u LengthUnit) millimetres() BigRational {
+	switch u {
+	case Centimetre:
+		return bigFraction(10, 1)
+	case Inch:
+		return bigFraction(254, 10) // exactly 25.4 mm, by definition
+	default:
+		return bigFraction(1, 1)
+	}
+}
+
+// Length returns the unit that lengths are measured in for a measurement system.
+func (u Unit) Length() LengthUnit {
+	if u == UnitImperial {
+		return Inch
+	}
+
+	return Centimetre
+}
+
+// Measurement is an exact length in a unit. The value is a BigRational, so
+// lengths of any size or number of digits convert without overflow.
+type Measurement struct {
+	Value BigRational `json:"value"`
+	Unit  LengthUnit  `json:"unit"`
+}
+
+// To converts m to another unit. Conversion factors are exact, so the result is too.
+func (m Measurement) To(u LengthUnit) Measurement {
+	mm := m.Value.Mul(m.Unit.millimetres())
+
+	// divide by the size of the new unit
+	per := new(big.Rat).Inv(u.millimetres().rat())
+
+	return Measurement{Value: mm.Mul(BigRational{per}), Unit: u}
+}
+
+// Float64 returns the nearest float64 value to the measurement, in its unit.
+func (m Measurement) Float64() float64 {
+	return m.Value.Float64()
+}
+
+// maxFractionDenominator is the finest fraction of an inch written without rounding.
+const maxFractionDenominator = 64
+
+// Format returns m for display with its unit. A positive fraction rounds the
+// value to the nearest 1/fraction, as in "5 1/2 in". Otherwise inches that are
+// a whole number of 64ths are written as a mixed number, and other values are
+// rounded to precision decimal places, as in "139.7 mm".
+func (m Measurement) Format(precision int, fraction int64) string {
+	x := Exact{A: m.Value}
+
+	if fraction <= 0 && m.Unit == Inch {
+		if q := m.Value.rat().Denom(); q.IsInt64() && maxFractionDenominator%q.Int64() == 0 {
+			fraction = maxFractionDenominator
+		}
+	}
+
+	return x.Format(precision, fraction) + " " + m.Unit.String()
+}
+
+// String returns m without rounding: as a mixed number in inches, and as a
+// decimal in metric units when the value has a finite decimal form.
+func (m Measurement) String() string {
+	if places, ok := decimalPlaces(m.Value.rat().Denom()); m.Unit != Inch && ok {
+		return m.Value.rat().FloatString(places) + " " + m.Unit.String()
+	}
+
+	return m.Value.Mixed() + " " + m.Unit.String()
+}
+
+// decimalPlaces returns the number of decimal places needed to write fractions
+// over q exactly, or false if they have no finite decimal form.
+func decimalPlaces(q *big.Int) (int, bool) {
+	q = new(big.Int).Set(q)
+	five, rem := big.NewInt(5), new(big.Int)
+
+	twos := int(q.TrailingZeroBits())
+	q.Rsh(q, uint(twos))
+
+	fives := 0
+	for q.Cmp(five) >= 0 {
+		quo, r := new(big.Int).QuoRem(q, five, rem)
+		if r.Sign() != 0 {
+			return 0, false
+		}
+
+		q = quo
+		fives++
+	}
+
+	if q.Int64() != 1 {
+		return 0, false
+	}
+
+	if twos > fives {
+		return twos, true
+	}
+
+	return fives, true
+}
+
+// vulgarFractions maps the fraction characters used in supplier specs to their text form.
+var vulgarFractions = strings.NewReplacer(
+	"½", " 1/2", "⅓", " 1/3", "⅔", " 2/3", "¼", " 1/4", "¾", " 3/4",
+	"⅛", " 1/8", "⅜", " 3/8", "⅝", " 5/8", "⅞", " 7/8",
+)
+
+// ParseMeasurement parses a length such as "5 1/2in", "5½ in", "14.8 cm" or
+// `3/4"`. A length without a unit is measured in def.
+func ParseMeasurement(s string, def LengthUnit) (Measurement, error) {
+	number, suffix := splitUnit(s)
+
+	m := Measurement{Unit: def}
+
+	if suffix != "" {
+		u, err := ParseLengthUnit(suffix)
+		if err != nil {
+			return Measurement{}, fmt.Errorf("%w: %q: %v", ErrSyntax, s, err)
+		}
+
+		m.Unit = u
+	}
+
+	v, err := ParseBigRational(number)
+	if err != nil {
+		return Measurement{}, fmt.Errorf("%w: %q: not a number", ErrSyntax, s)
+	}
+
+	m.Value = v
+
+	return m, nil
+}
+
+// splitUnit splits a length into its number and its unit, which is the
+// trailing run of letters and inch marks and may be empty.
+func splitUnit(s string) (string, string) {
+	text := strings.TrimSpace(vulgarFractions.Replace(s))
+
+	i := strings.LastIndexFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '"' && r != '\'' && r != '″'
+	})
+
+	return strings.TrimSpace(text[:i+1]), text[i+1:]
+}
+
+// parseSides parses the sides of a size such as "7 x 5 in", "12in x 12" or
+// "18x13cmx0.5mm". A side without a unit takes the unit of the next side that
+// has one, or else the previous one, so a unit written once applies to every
+// side. A size without any unit is measured in def.
+func parseSides(parts []string, def LengthUnit) ([]Measurement, error) {
+	sides := make([]Measurement, len(parts))
+	given := make([]bool, len(parts))
+
+	for i, p := range parts {
+		m, err := ParseMeasurement(p, def)
+		if err != nil {
+			return nil, err
+		}
+
+		_, suffix := splitUnit(p)
+		sides[i], given[i] = m, suffix != ""
+	}
+
+	// carry units back from the side after, then forward from the side before
+	for i := len(sides) - 2; i >= 0; i-- {
+		if !given[i] && given[i+1] {
+			sides[i].Unit, given[i] = sides[i+1].Unit, true
+		}
+	}
+
+	for i := 1; i < len(sides); i++ {
+		if !given[i] && given[i-1] {
+			sides[i].Unit, given[i] = sides[i-1].Unit, true
+		}
+	}
+
+	return sides, nil
+}
@@ -0,0 +1,106 @@
+package calculate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLengthUnit(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      LengthUnit
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "mm", arg: "mm", want: Millimetre, assertion: assert.NoError},
+		{name: "millimetres", arg: "Millimetres", want: Millimetre, assertion: assert.NoError},
+		{name: "cm", arg: " cm ", want: Centimetre, assertion: assert.NoError},
+		{name: "inch mark", arg: `"`, want: Inch, assertion: assert.NoError},
+		{name: "inches", arg: "INCHES", want: Inch, assertion: assert.NoError},
+		{name: "unknown", arg: "furlong", want: Millimetre, assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLengthUnit(tt.arg)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseMeasurement(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      Measurement
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "mixed inches", arg: "5 1/2in", want: Measurement{bigFraction(11, 2), Inch}, assertion: assert.NoError},
+		{name: "vulgar fraction", arg: "5½ in", want: Measurement{bigFraction(11, 2), Inch}, assertion: assert.NoError},
+		{name: "inch mark", arg: `3/4"`, want: Measurement{bigFraction(3, 4), Inch}, assertion: assert.NoError},
+		{name: "decimal cm", arg: "14.8 cm", want: Measurement{bigFraction(74, 5), Centimetre}, assertion: assert.NoError},
+		{name: "exponent", arg: "1e2mm", want: Measurement{bigFraction(100, 1), Millimetre}, assertion: assert.NoError},
+		{name: "default unit", arg: "7", want: Measurement{bigFraction(7, 1), Centimetre}, assertion: assert.NoError},
+		{name: "unknown unit", arg: "5 furlongs", assertion: assert.Error},
+		{name: "no number", arg: "in", assertion: assert.Error},
+		{name: "empty", arg: "", assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMeasurement(tt.arg, Centimetre)
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMeasurement_To(t *testing.T) {
+	inch := Measurement{bigFraction(1, 1), Inch}
+
+	assert.Equal(t, Measurement{bigFraction(127, 5), Millimetre}, inch.To(Millimetre))
+	assert.Equal(t, Measurement{bigFraction(127, 50), Centimetre}, inch.To(Centimetre))
+	assert.Equal(t, inch, inch.To(Centimetre).To(Millimetre).To(Inch))
+	assert.Equal(t, Measurement{bigFraction(11, 2), Inch}, Measurement{bigFraction(1397, 10), Millimetre}.To(Inch))
+
+	// values whose conversion does not fit in an int64
+	large, err := ParseMeasurement("100000000000000000in", Inch)
+	assert.NoError(t, err)
+	assert.Equal(t, "2540000000000000000 mm", large.To(Millimetre).String())
+
+	long, err := ParseMeasurement("0.123456789012345678in", Inch)
+	assert.NoError(t, err)
+	assert.Equal(t, "3.1358024409135802212 mm", long.To(Millimetre).String())
+	assert.Equal(t, "3.135802 mm", long.To(Millimetre).Format(6, 0))
+}
+
+func TestMeasurement_Format(t *testing.T) {
+	tests := []struct {
+		name      string
+		m         Measurement
+		precision int
+		fraction  int64
+		want      string
+	}{
+		{name: "decimal", m: Measurement{bigFraction(1397, 10), Millimetre}, precision: 1, want: "139.7 mm"},
+		{name: "rounded", m: Measurement{bigFraction(1, 3), Centimetre}, precision: 2, want: "0.33 cm"},
+		{name: "exact inches", m: Measurement{bigFraction(11, 2), Inch}, precision: 1, want: "5 1/2 in"},
+		{name: "inexact inches", m: Measurement{bigFraction(1, 3), Inch}, precision: 3, want: "0.333 in"},
+		{name: "fraction", m: Measurement{bigFraction(1, 3), Inch}, fraction: 16, want: "5/16 in"},
+		{name: "fraction of cm", m: Measurement{bigFraction(74, 5), Centimetre}, fraction: 2, want: "15 cm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.m.Format(tt.precision, tt.fraction))
+		})
+	}
+}
+
+func TestMeasurement_String(t *testing.T) {
+	assert.Equal(t, "14.8 cm", Measurement{bigFraction(74, 5), Centimetre}.String())
+	assert.Equal(t, "1/3 cm", Measurement{bigFraction(1, 3), Centimetre}.String())
+	assert.Equal(t, "5 1/2 in", Measurement{bigFraction(11, 2), Inch}.String())
+	assert.Equal(t, "0.0625 cm", Measurement{bigFraction(1, 16), Centimetre}.String())
+}
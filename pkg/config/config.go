@@ -0,0 +1,316 @@
+// Package config describes the application's configuration settings and validates config files.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+// ErrInvalid is returned when a config file does not match the supported settings.
+var ErrInvalid = errors.New("invalid config")
+
+// EnvPrefix is the prefix for environment variables that override settings.
+const EnvPrefix = "PBCALC"
+
+// Built-in values for settings that are not configured.
+const (
+	DefaultLoggingLevel = "warn"
+	DefaultUnits        = "cm"
+	DefaultBoard        = "standard"
+	DefaultContent      = "snug"
+	DefaultPrecision    = 1
+	DefaultStockSizes   = "none"
+	DefaultOutputFormat = "text"
+	DefaultRetention    = 1000
+)
+
+// MaxPrecision is the largest number of decimal places results can be shown with.
+const MaxPrecision = 6
+
+// Key describes a single configuration setting.
+type Key struct {
+	Name        string             // dotted path of the setting, e.g. "logging.level"
+	Default     any                // built-in value
+	Description string             // one line description, used in the default config file
+	Validate    func(string) error // checks a scalar value; nil accepts any value
+}
+
+// Keys lists every supported setting, grouped by section.
+var Keys = []Key{
+	{
+		Name:        "logging.level",
+		Default:     DefaultLoggingLevel,
+		Description: "log level: trace, debug, info, warn, error, fatal or panic",
+		Validate:    validateLogLevel,
+	},
+	{
+		Name:        "defaults.units",
+		Default:     DefaultUnits,
+		Description: "measurement units: cm or in",
+		Validate:    parses(calculate.ParseUnit),
+	},
+	{
+		Name:        "defaults.board",
+		Default:     DefaultBoard,
+		Description: "punch board: standard or mini",
+		Validate:    parses(calculate.ParseBoard),
+	},
+	{
+		Name:        "defaults.content",
+		Default:     DefaultContent,
+		Description: "content fit: snug or loose",
+		Validate:    parses(calculate.ParseContent),
+	},
+	{
+		Name:        "defaults.precision",
+		Default:     DefaultPrecision,
+		Description: "decimal places shown in results: 0 to " + strconv.Itoa(MaxPrecision),
+		Validate:    validatePrecision,
+	},
+	{
+		Name:        "defaults.stock_sizes",
+		Default:     DefaultStockSizes,
+		Description: "square paper sizes to round envelopes up to, e.g. 6, 8.5, 12in; standard or none",
+		Validate:    validateStockSizes,
+	},
+	{
+		Name:        "output.format",
+		Default:     DefaultOutputFormat,
+		Description: "output format: text or json",
+		Validate:    oneOf("text", "json"),
+	},
+	{
+		Name:        "history.retention",
+		Default:     DefaultRetention,
+		Description: "calculations kept in the history file; 0 to keep no history",
+		Validate:    validateRetention,
+	},
+}
+
+// Lookup returns the setting with the given name.
+func Lookup(name string) (Key, bool) {
+	for _, k := range Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+
+	return Key{}, false
+}
+
+// EnvVar returns the environment variable that overrides a setting.
+func EnvVar(name string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+}
+
+func validateLogLevel(s string) error {
+	_, err := log.ParseLevel(s)
+	return err
+}
+
+// parses adapts a parser into a validation function that accepts anything it can parse.
+func parses[T any](parse func(string) (T, error)) func(string) error {
+	return func(s string) error {
+		_, err := parse(s)
+		return err
+	}
+}
+
+// ParsePrecision parses a number of decimal places.
+func ParsePrecision(s string) (int, error) {
+	p, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || p < 0 || p > MaxPrecision {
+		return 0, fmt.Errorf("precision must be a whole number from 0 to %d, got %q", MaxPrecision, s)
+	}
+
+	return p, nil
+}
+
+func validatePrecision(s string) error {
+	_, err := ParsePrecision(s)
+	return err
+}
+
+// ParseRetention parses the number of calculations kept in the history file.
+func ParseRetention(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("retention must be a whole number from 0, got %q", s)
+	}
+
+	return n, nil
+}
+
+func validateRetention(s string) error {
+	_, err := ParseRetention(s)
+	return err
+}
+
+// validateStockSizes accepts a stock size list. Sizes without a unit are
+// measured in the units in use, which do not affect whether the list parses.
+func validateStockSizes(s string) error {
+	_, err := calculate.ParseStockSizes(s, calculate.UnitMetric)
+	return err
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(s string) error {
+		for _, v := range values {
+			if s == v {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("must be one of %s, got %q", strings.Join(values, ", "), s)
+	}
+}
+
+// Default returns a commented config file holding the built-in value of every setting.
+func Default() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("# pbc configuration\n")
+	buf.WriteString("#\n")
+	buf.WriteString("# Every setting can be overridden with a " + EnvPrefix + "_* environment variable,\n")
+	buf.WriteString("# e.g. " + EnvVar("logging.level") + "=debug, and by command line flags.\n")
+
+	var section string
+
+	for _, k := range Keys {
+		parts := strings.Split(k.Name, ".")
+
+		if parts[0] != section {
+			section = parts[0]
+
+			buf.WriteString("\n" + section + ":\n")
+		}
+
+		indent := strings.Repeat("  ", len(parts)-1)
+
+		fmt.Fprintf(&buf, "%s# %s\n", indent, k.Description)
+		fmt.Fprintf(&buf, "%s%s: %v\n", indent, parts[len(parts)-1], k.Default)
+	}
+
+	return buf.Bytes()
+}
+
+// ValidationError is a problem found at a specific place in a config file.
+type ValidationError struct {
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Key    string `json:"key,omitempty"`
+	Msg    string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	pos := fmt.Sprintf("%d:%d", e.Line, e.Column)
+	if e.Column == 0 {
+		pos = fmt.Sprintf("%d", e.Line)
+	}
+
+	if e.Key == "" {
+		return pos + ": " + e.Msg
+	}
+
+	return pos + ": " + e.Key + ": " + e.Msg
+}
+
+// Validate checks a YAML config file against the supported settings and
+// returns every problem found, in file order.
+func Validate(data []byte) []*ValidationError {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []*ValidationError{syntaxError(err)}
+	}
+
+	// an empty file has no content
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []*ValidationError{{Line: root.Line, Column: root.Column, Msg: "expected a mapping of settings"}}
+	}
+
+	return validateMapping(root, "")
+}
+
+// syntaxError converts a YAML parse error into a ValidationError, keeping its line number.
+func syntaxError(err error) *ValidationError {
+	ve := &ValidationError{Msg: strings.TrimPrefix(err.Error(), "yaml: ")}
+
+	if _, err := fmt.Sscanf(ve.Msg, "line %d:", &ve.Line); err == nil {
+		ve.Msg = strings.TrimSpace(ve.Msg[strings.Index(ve.Msg, ":")+1:])
+	}
+
+	return ve
+}
+
+func validateMapping(node *yaml.Node, prefix string) []*ValidationError {
+	var errs []*ValidationError
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valNode := node.Content[i], node.Content[i+1]
+
+		name := keyNode.Value
+		if prefix != "" {
+			name = prefix + "." + keyNode.Value
+		}
+
+		if k, ok := Lookup(name); ok {
+			errs = append(errs, validateValue(k, valNode)...)
+			continue
+		}
+
+		if !isSection(name) {
+			errs = append(errs, &ValidationError{Line: keyNode.Line, Column: keyNode.Column, Key: name, Msg: "unknown setting"})
+			continue
+		}
+
+		if valNode.Kind != yaml.MappingNode {
+			errs = append(errs, &ValidationError{Line: valNode.Line, Column: valNode.Column, Key: name, Msg: "expected a mapping"})
+			continue
+		}
+
+		errs = append(errs, validateMapping(valNode, name)...)
+	}
+
+	return errs
+}
+
+// isSection reports whether name is the parent of at least one setting.
+func isSection(name string) bool {
+	for _, k := range Keys {
+		if strings.HasPrefix(k.Name, name+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateValue(k Key, node *yaml.Node) []*ValidationError {
+	if node.Kind != yaml.ScalarNode {
+		return []*ValidationError{{Line: node.Line, Column: node.Column, Key: k.Name, Msg: "expected a single value"}}
+	}
+
+	if k.Validate == nil {
+		return nil
+	}
+
+	if err := k.Validate(node.Value); err != nil {
+		return []*ValidationError{{Line: node.Line, Column: node.Column, Key: k.Name, Msg: err.Error()}}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvVar(t *testing.T) {
+	assert.Equal(t, "PBCALC_LOGGING_LEVEL", EnvVar("logging.level"))
+}
+
+func TestLookup(t *testing.T) {
+	got, ok := Lookup("logging.level")
+	require.True(t, ok)
+	assert.Equal(t, DefaultLoggingLevel, got.Default)
+
+	_, ok = Lookup("logging")
+	assert.False(t, ok)
+}
+
+func TestDefault(t *testing.T) {
+	got := Default()
+
+	assert.Contains(t, string(got), "logging:\n  # log level")
+	assert.Contains(t, string(got), "  level: warn\n")
+	assert.Empty(t, Validate(got))
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "empty",
+			data: "",
+			want: nil,
+		},
+		{
+			name: "valid",
+			data: "logging:\n  level: debug\n",
+			want: nil,
+		},
+		{
+			name: "syntax error",
+			data: "logging:\n  level: debug\n bad\n",
+			want: []string{"2: did not find expected key"},
+		},
+		{
+			name: "not a mapping",
+			data: "- logging\n",
+			want: []string{"1:1: expected a mapping of settings"},
+		},
+		{
+			name: "unknown setting",
+			data: "logging:\n  level: info\n  colour: red\nlogs: true\n",
+			want: []string{
+				"3:3: logging.colour: unknown setting",
+				"4:1: logs: unknown setting",
+			},
+		},
+		{
+			name: "invalid value",
+			data: "logging:\n  level: loud\n",
+			want: []string{`2:10: logging.level: not a valid logrus Level: "loud"`},
+		},
+		{
+			name: "stock sizes",
+			data: "defaults:\n  stock_sizes: 6in, 8.5in, 30.5cm\n",
+			want: nil,
+		},
+		{
+			name: "invalid stock size",
+			data: "defaults:\n  stock_sizes: 6, 8x\n",
+			want: []string{`2:16: defaults.stock_sizes: invalid syntax: "8x": unknown unit "x"`},
+		},
+		{
+			name: "history retention",
+			data: "history:\n  retention: 50\n",
+			want: nil,
+		},
+		{
+			name: "negative history retention",
+			data: "history:\n  retention: -1\n",
+			want: []string{`2:14: history.retention: retention must be a whole number from 0, got "-1"`},
+		},
+		{
+			name: "section is not a mapping",
+			data: "logging: debug\n",
+			want: []string{"1:10: logging: expected a mapping"},
+		},
+		{
+			name: "value is not a scalar",
+			data: "logging:\n  level:\n    - debug\n",
+			want: []string{"3:5: logging.level: expected a single value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range Validate([]byte(tt.data)) {
+				got = append(got, e.Error())
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
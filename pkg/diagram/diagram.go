@@ -0,0 +1,169 @@
+// Package diagram renders envelope layouts as text and SVG.
+package diagram
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+// DefaultWidth is the default number of columns used for a text diagram.
+const DefaultWidth = 32
+
+const (
+	minWidth    = 4
+	cellAspect  = 2   // terminal cells are roughly twice as tall as they are wide
+	strokeScale = 200 // paper size to stroke width ratio
+	dashLength  = 5   // dash length in stroke widths
+	dashSpacing = 2   // space between dashes in stroke widths
+	corners     = 4
+)
+
+type point struct {
+	x, y float64
+}
+
+// contentCorners returns the corners of the content on the paper, in drawing order.
+// The content sits diagonally on the paper with each corner a margin away from an edge,
+// in the envelope's orientation, and its top corner lined up with the punch location.
+func contentCorners(env calculate.Envelope) []point {
+	_, _, m := env.Sheet()
+	a, b := env.Sides()
+
+	return []point{
+		{m, m + a},
+		{m + b, m + a + b},
+		{m + a + b, m + b},
+		{m + a, m},
+	}
+}
+
+// inside reports whether p is within the convex polygon.
+func inside(poly []point, p point) bool {
+	var sign float64
+
+	for i := range poly {
+		a, b := poly[i], poly[(i+1)%len(poly)]
+
+		cross := (b.x-a.x)*(p.y-a.y) - (b.y-a.y)*(p.x-a.x)
+		if cross == 0 {
+			continue
+		}
+
+		if sign == 0 {
+			sign = cross
+		} else if (sign > 0) != (cross > 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// onLiner reports whether p is within the liner.
+func onLiner(l *calculate.Liner, p point) bool {
+	if l == nil {
+		return false
+	}
+
+	end := l.Inset + l.Size
+
+	return p.x >= l.Inset && p.x <= end && p.y >= l.Inset && p.y <= end
+}
+
+// ASCII renders the envelope layout as text, width columns wide.
+//
+// The paper is drawn with '.', the liner, if any, with ':', the content with
+// '#' and the first punch location is marked with 'v' on the top edge.
+func ASCII(env calculate.Envelope, width int) string {
+	if width < minWidth {
+		width = DefaultWidth
+	}
+
+	height := width / cellAspect
+	poly := contentCorners(env)
+	paper, punch, _ := env.Sheet()
+	cellW := paper / float64(width)
+	cellH := paper / float64(height)
+	punchCol := int(punch / cellW)
+
+	var sb strings.Builder
+
+	sb.WriteString("+")
+	for col := 0; col < width; col++ {
+		if col == punchCol {
+			sb.WriteString("v")
+		} else {
+			sb.WriteString("-")
+		}
+	}
+	sb.WriteString("+\n")
+
+	for row := 0; row < height; row++ {
+		sb.WriteString("|")
+		for col := 0; col < width; col++ {
+			center := point{(float64(col) + 0.5) * cellW, (float64(row) + 0.5) * cellH}
+			switch {
+			case inside(poly, center):
+				sb.WriteString("#")
+			case onLiner(env.Liner, center):
+				sb.WriteString(":")
+			default:
+				sb.WriteString(".")
+			}
+		}
+		sb.WriteString("|\n")
+	}
+
+	sb.WriteString("+" + strings.Repeat("-", width) + "+\n")
+
+	return sb.String()
+}
+
+// SVG renders the envelope layout as a standalone SVG document drawn to scale.
+func SVG(env calculate.Envelope) string {
+	const (
+		paperFill   = "#e0e0e0"
+		linerFill   = "#f4cccc"
+		contentFill = "#38761D"
+		foldStroke  = "#888888"
+		punchStroke = "#cc0000"
+	)
+
+	size, punch, margin := env.Sheet()
+	unit := env.Spec.Unit
+	stroke := size / strokeScale
+
+	points := make([]string, 0, corners)
+	for _, p := range contentCorners(env) {
+		points = append(points, fmt.Sprintf("%.4f,%.4f", p.x, p.y))
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.4f%s" height="%.4f%s" viewBox="0 0 %.4f %.4f">`+"\n",
+		size, unit, size, unit, size, size)
+	fmt.Fprintf(&sb, `  <rect x="0" y="0" width="%.4f" height="%.4f" fill="%s"/>`+"\n", size, size, paperFill)
+
+	if l := env.Liner; l != nil {
+		fmt.Fprintf(&sb, `  <rect x="%.4f" y="%.4f" width="%.4f" height="%.4f" fill="%s"/>`+"\n",
+			l.Inset, l.Inset, l.Size, l.Size, linerFill)
+
+		for _, f := range l.Folds {
+			x1, y1 := calculate.MarkPoint(f[0], l.Size)
+			x2, y2 := calculate.MarkPoint(f[1], l.Size)
+
+			fmt.Fprintf(&sb, `  <line x1="%.4f" y1="%.4f" x2="%.4f" y2="%.4f" stroke="%s" stroke-width="%.4f" stroke-dasharray="%.4f %.4f"/>`+"\n",
+				l.Inset+x1, l.Inset+y1, l.Inset+x2, l.Inset+y2, foldStroke, stroke, stroke*dashLength, stroke*dashSpacing)
+		}
+	}
+
+	fmt.Fprintf(&sb, `  <polygon points="%s" fill="%s" stroke="%s" stroke-width="%.4f" stroke-dasharray="%.4f %.4f"/>`+"\n",
+		strings.Join(points, " "), contentFill, foldStroke, stroke, stroke*dashLength, stroke*dashSpacing)
+	fmt.Fprintf(&sb, `  <line x1="%.4f" y1="0" x2="%.4f" y2="%.4f" stroke="%s" stroke-width="%.4f"/>`+"\n",
+		punch, punch, margin, punchStroke, stroke)
+	sb.WriteString("</svg>\n")
+
+	return sb.String()
+}
@@ -0,0 +1,123 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func testEnvelope(t *testing.T) calculate.Envelope {
+	t.Helper()
+
+	env, err := calculate.EnvelopeSpec{Length: 10, Width: 8, Loose: true}.Calculate()
+	require.NoError(t, err)
+
+	return env
+}
+
+func TestASCII(t *testing.T) {
+	tests := []struct {
+		name      string
+		width     int
+		wantWidth int
+	}{
+		{name: "default width", width: 0, wantWidth: DefaultWidth},
+		{name: "custom width", width: 20, wantWidth: 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ASCII(testEnvelope(t), tt.width)
+
+			lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+			assert.Len(t, lines, tt.wantWidth/2+2)
+
+			for _, l := range lines {
+				assert.Len(t, l, tt.wantWidth+2)
+			}
+
+			assert.Contains(t, lines[0], "v")
+			assert.Contains(t, got, "#")
+		})
+	}
+}
+
+func TestASCII_CornersArePaper(t *testing.T) {
+	got := ASCII(testEnvelope(t), 20)
+	lines := strings.Split(got, "\n")
+
+	assert.Equal(t, byte('.'), lines[1][1])
+	assert.Equal(t, byte('.'), lines[1][20])
+	assert.Equal(t, byte('#'), lines[5][10])
+}
+
+func TestSVG(t *testing.T) {
+	got := SVG(testEnvelope(t))
+
+	assert.True(t, strings.HasPrefix(got, "<svg "))
+	assert.Contains(t, got, `width="15.7279cm"`)
+	assert.Contains(t, got, "<polygon ")
+	assert.True(t, strings.HasSuffix(got, "</svg>\n"))
+}
+
+func TestSVG_StockSheet(t *testing.T) {
+	env, err := calculate.EnvelopeSpec{Length: 10, Width: 8, StockSizes: []float64{20}}.Calculate()
+	require.NoError(t, err)
+
+	got := SVG(env)
+
+	assert.Contains(t, got, `width="20.0000cm"`)
+	assert.Contains(t, got, fmt.Sprintf(`x1="%.4f"`, env.Stock.PunchLocation))
+}
+
+func TestLiner(t *testing.T) {
+	env, err := calculate.EnvelopeSpec{Length: 10, Width: 8, Loose: true, LinerInset: 1}.Calculate()
+	require.NoError(t, err)
+
+	lines := strings.Split(ASCII(env, 20), "\n")
+	assert.Equal(t, byte('.'), lines[1][1], "paper outside the liner")
+	assert.Equal(t, byte(':'), lines[2][3], "liner outside the content")
+	assert.Equal(t, byte('#'), lines[5][10])
+
+	got := SVG(env)
+	assert.Contains(t, got, `<rect x="1.0000" y="1.0000" width="13.7279" height="13.7279"`)
+	assert.Equal(t, 5, strings.Count(got, "<line "), "four folds and the punch")
+
+	assert.NotContains(t, ASCII(testEnvelope(t), 20), ":")
+	assert.NotContains(t, SVG(testEnvelope(t)), `fill="#f4cccc"`)
+}
+
+func TestOrientation(t *testing.T) {
+	width, err := calculate.EnvelopeSpec{Length: 10, Width: 8, Orientation: calculate.OrientationWidth}.Calculate()
+	require.NoError(t, err)
+
+	length, err := calculate.EnvelopeSpec{Length: 10, Width: 8, Orientation: calculate.OrientationLength}.Calculate()
+	require.NoError(t, err)
+
+	// the layouts are mirror images, with the punch marked over the top corner
+	w := strings.Split(ASCII(width, 20), "\n")
+	l := strings.Split(ASCII(length, 20), "\n")
+
+	for i := 1; i < len(w)-2; i++ {
+		assert.Equal(t, reverse(w[i]), l[i], "row %d", i)
+	}
+
+	assert.NotEqual(t, strings.Index(w[0], "v"), strings.Index(l[0], "v"))
+
+	wantTop := fmt.Sprintf("%.4f,%.4f", length.PunchLocation, length.Margin)
+	assert.Contains(t, SVG(length), wantTop)
+	assert.NotContains(t, SVG(width), wantTop)
+}
+
+func reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+
+	return string(b)
+}
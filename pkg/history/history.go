@@ -0,0 +1,304 @@
+// Package history keeps a log of calculations in a JSON Lines file that several processes can append to at once.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the history file in the application directory.
+const FileName = "history.jsonl"
+
+// ErrNotFound is returned when there is no calculation with the requested ID.
+var ErrNotFound = errors.New("calculation not found")
+
+// ErrLocked is returned when the history file stays locked by another process.
+var ErrLocked = errors.New("history file is locked")
+
+const (
+	lockRetry   = 10 * time.Millisecond
+	lockTimeout = 5 * time.Second
+	maxLine     = 1 << 20 // longest entry that can be read; longer lines are skipped
+)
+
+// errLockHeld is returned by tryLock when another process holds the lock.
+var errLockHeld = errors.New("lock is held")
+
+// dimensionTolerance is how close a dimension must be to match a filter.
+const dimensionTolerance = 1e-9
+
+// Entry is one calculation in the history.
+type Entry struct {
+	ID         int                `json:"id"`
+	Time       time.Time          `json:"time"`
+	Command    string             `json:"command"`              // subcommand that made the calculation, such as envelope
+	Args       []string           `json:"args"`                 // flags to run the calculation again with the same settings
+	Board      string             `json:"board"`                // punch board used
+	Unit       string             `json:"unit"`                 // units of the dimensions and result
+	Dimensions map[string]float64 `json:"dimensions,omitempty"` // named input dimensions, such as length and width
+	Result     json.RawMessage    `json:"result"`               // result as written by --output json
+}
+
+// Filter selects entries. Zero fields match every entry.
+type Filter struct {
+	Command   string    // subcommand
+	Dimension float64   // any input dimension
+	Since     time.Time // earliest time, inclusive
+	Until     time.Time // latest time, exclusive
+}
+
+// Match reports whether an entry passes the filter.
+func (f Filter) Match(e Entry) bool {
+	if f.Command != "" && f.Command != e.Command {
+		return false
+	}
+
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+
+	if f.Dimension == 0 {
+		return true
+	}
+
+	for _, d := range e.Dimensions {
+		if math.Abs(d-f.Dimension) < dimensionTolerance {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Select returns the entries that pass the filter, in order.
+func Select(entries []Entry, f Filter) []Entry {
+	var selected []Entry
+
+	for _, e := range entries {
+		if f.Match(e) {
+			selected = append(selected, e)
+		}
+	}
+
+	return selected
+}
+
+// Find returns the entry with the given ID.
+func Find(entries []Entry, id int) (Entry, error) {
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+
+	return Entry{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+}
+
+// Read returns every entry in a history file, oldest first, or none if the
+// file does not exist. Lines that cannot be read, such as one cut short when
+// a process died while writing it or one longer than maxLine, are skipped.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var entries []Entry
+
+	r := bufio.NewReader(f)
+
+	for {
+		line, err := readLine(r)
+
+		var e Entry
+		if len(line) > 0 && json.Unmarshal(line, &e) == nil {
+			entries = append(entries, e)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readLine returns the next line from r without its newline, or nil if the
+// line is longer than maxLine. A long line is read to its end but not kept.
+func readLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+
+	tooLong := false
+
+	for {
+		chunk, err := r.ReadSlice('\n')
+
+		if !tooLong && len(line)+len(chunk) > maxLine+1 {
+			tooLong, line = true, nil
+		}
+
+		if !tooLong {
+			line = append(line, chunk...)
+		}
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+
+		return bytes.TrimSuffix(line, []byte("\n")), err
+	}
+}
+
+// Append adds an entry to a history file, creating the file and its directory
+// if needed, and returns it with its ID. Only the newest retention entries are
+// kept; zero or less keeps every entry.
+//
+// The file is locked while it is read and written, so entries appended by
+// several processes at once each get their own ID and none are lost.
+func Append(path string, e Entry, retention int) (Entry, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return Entry{}, err
+	}
+
+	unlock, err := lock(path)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	defer unlock()
+
+	entries, err := Read(path)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	e.ID = 1
+	if len(entries) > 0 {
+		e.ID = entries[len(entries)-1].ID + 1
+	}
+
+	entries = append(entries, e)
+
+	if retention > 0 && len(entries) > retention {
+		return e, rewrite(path, entries[len(entries)-retention:])
+	}
+
+	line, err := encode(e)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return Entry{}, err
+	}
+
+	return e, f.Close()
+}
+
+// encode returns an entry as a single line of JSON.
+func encode(e Entry) ([]byte, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// rewrite replaces a history file with the given entries. They are written
+// to a temporary file that is renamed over it, so readers never see a partial file.
+func rewrite(path string, entries []Entry) error {
+	var buf bytes.Buffer
+
+	for _, e := range entries {
+		line, err := encode(e)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(line)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// lock takes the lock on a history file, waiting for another process to
+// release it, and returns the function that releases it. The lock is held on a
+// file beside the history file by the operating system, which releases it if
+// the process dies, so a crash never leaves the history locked. The lock file
+// itself is left in place.
+func lock(path string) (func(), error) {
+	name := path + ".lock"
+
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		err := tryLock(f)
+		if err == nil {
+			return func() {
+				unlockFile(f)
+				f.Close()
+			}, nil
+		}
+
+		if !errors.Is(err, errLockHeld) {
+			f.Close()
+			return nil, err
+		}
+
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w: another pbc is still writing %s", ErrLocked, path)
+		}
+
+		time.Sleep(lockRetry)
+	}
+}
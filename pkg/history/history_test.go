@@ -0,0 +1,196 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(d int) time.Time {
+	return time.Date(2026, time.October, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".pbc", FileName)
+
+	for i := 0; i < 3; i++ {
+		e, err := Append(path, Entry{Command: "envelope", Time: day(i + 1), Result: json.RawMessage(`{"paper_size":9.4}`)}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, i+1, e.ID)
+	}
+
+	got, err := Read(path)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, day(3), got[2].Time)
+	assert.JSONEq(t, `{"paper_size":9.4}`, string(got[2].Result))
+
+	// the lock is released
+	unlock, err := lock(path)
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestAppend_Retention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	for i := 0; i < 5; i++ {
+		_, err := Append(path, Entry{Command: "box"}, 3)
+		require.NoError(t, err)
+	}
+
+	got, err := Read(path)
+	require.NoError(t, err)
+
+	ids := make([]int, len(got))
+	for i, e := range got {
+		ids[i] = e.ID
+	}
+
+	assert.Equal(t, []int{3, 4, 5}, ids)
+}
+
+func TestAppend_Concurrent(t *testing.T) {
+	const n = 20
+
+	path := filepath.Join(t.TempDir(), FileName)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := Append(path, Entry{Command: "tag"}, 0)
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	got, err := Read(path)
+	require.NoError(t, err)
+	require.Len(t, got, n)
+
+	ids := make([]int, n)
+	for i, e := range got {
+		ids[i] = e.ID
+	}
+
+	sort.Ints(ids)
+
+	for i, id := range ids {
+		assert.Equal(t, i+1, id)
+	}
+}
+
+func TestAppend_Locked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	// a lock file left by a process that died does not hold the lock
+	require.NoError(t, os.WriteFile(path+".lock", nil, 0o600))
+
+	unlock, err := lock(path)
+	require.NoError(t, err)
+
+	done := make(chan Entry)
+
+	go func() {
+		e, err := Append(path, Entry{Command: "bag"}, 0)
+		assert.NoError(t, err)
+		done <- e
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("appended while the history was locked")
+	case <-time.After(10 * lockRetry):
+	}
+
+	unlock()
+
+	assert.Equal(t, 1, (<-done).ID)
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+
+	got, err := Read(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	path := filepath.Join(dir, FileName)
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":1,"command":"envelope"}`+"\n"+`{"id":2,"comm`), 0o600))
+
+	got, err = Read(path)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "envelope", got[0].Command)
+}
+
+func TestRead_LongLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	long := `{"id":2,"command":"` + strings.Repeat("x", 2*maxLine) + `"}`
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":1}`+"\n"+long+"\n"+`{"id":3}`+"\n"), 0o600))
+
+	got, err := Read(path)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, 1, got[0].ID)
+	assert.Equal(t, 3, got[1].ID)
+
+	// the history can still be appended to
+	e, err := Append(path, Entry{Command: "tag"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 4, e.ID)
+}
+
+func TestFilter_Match(t *testing.T) {
+	e := Entry{ID: 1, Time: day(10), Command: "envelope", Dimensions: map[string]float64{"length": 7, "width": 5}}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "empty", filter: Filter{}, want: true},
+		{name: "command", filter: Filter{Command: "envelope"}, want: true},
+		{name: "other command", filter: Filter{Command: "box"}, want: false},
+		{name: "dimension", filter: Filter{Dimension: 5}, want: true},
+		{name: "other dimension", filter: Filter{Dimension: 6}, want: false},
+		{name: "since", filter: Filter{Since: day(10)}, want: true},
+		{name: "too old", filter: Filter{Since: day(11)}, want: false},
+		{name: "until", filter: Filter{Until: day(11)}, want: true},
+		{name: "too new", filter: Filter{Until: day(10)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(e))
+		})
+	}
+}
+
+func TestSelectAndFind(t *testing.T) {
+	entries := []Entry{{ID: 4, Command: "box"}, {ID: 5, Command: "envelope"}, {ID: 6, Command: "box"}}
+
+	got := Select(entries, Filter{Command: "box"})
+	assert.Equal(t, []Entry{entries[0], entries[2]}, got)
+
+	e, err := Find(entries, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "envelope", e.Command)
+
+	_, err = Find(entries, 7)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
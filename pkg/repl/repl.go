@@ -0,0 +1,256 @@
+// Package repl implements the line-oriented calculator used by the repl command and stdin mode.
+package repl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+// ErrSyntax is returned for a line that cannot be understood.
+var ErrSyntax = errors.New("syntax error")
+
+// envelopeDimensions is the number of dimensions needed for an envelope: length and width.
+const envelopeDimensions = 2
+
+// Session holds the settings that carry over from one line to the next.
+type Session struct {
+	Board     calculate.Board
+	Unit      calculate.Unit
+	Loose     bool // content fit for lines that do not give one
+	Precision int  // decimal places in results
+	JSON      bool // write each result as a single line of JSON
+}
+
+// line is the parsed form of a single input line, with the settings it leaves
+// in place for the lines after it.
+type line struct {
+	dims  []float64
+	loose bool
+	fit   string // content fit keyword, which applies to this line only
+	board calculate.Board
+	unit  calculate.Unit
+}
+
+// Eval parses a line and returns its calculation. A line without dimensions only
+// updates the board and unit settings and returns a zero Envelope; the content
+// fit does not carry over, so such a line cannot give one. A line that cannot
+// be understood leaves the settings unchanged.
+//
+// A line holds a length and width, optionally separated by "x", followed by any
+// of the keywords: thick/loose, snug/flat, mini/standard, in/cm. Units may also
+// be attached to a dimension, as in "5.5in".
+func (s *Session) Eval(text string) (calculate.Envelope, error) {
+	l, err := s.parse(text)
+	if err != nil {
+		return calculate.Envelope{}, err
+	}
+
+	if len(l.dims) != 0 && len(l.dims) != envelopeDimensions {
+		return calculate.Envelope{}, fmt.Errorf("%w: expected length and width, got %d dimensions", ErrSyntax, len(l.dims))
+	}
+
+	if len(l.dims) == 0 && l.fit != "" {
+		return calculate.Envelope{}, fmt.Errorf("%w: %q only applies to a line with a length and width", ErrSyntax, l.fit)
+	}
+
+	s.Board, s.Unit = l.board, l.unit
+
+	if len(l.dims) == 0 {
+		return calculate.Envelope{}, nil
+	}
+
+	return calculate.EnvelopeSpec{
+		Length: l.dims[0],
+		Width:  l.dims[1],
+		Loose:  l.loose,
+		Board:  l.board,
+		Unit:   l.unit,
+	}.Calculate()
+}
+
+// parse reads a line without changing the session.
+func (s *Session) parse(text string) (line, error) {
+	l := line{loose: s.Loose, board: s.Board, unit: s.Unit}
+
+	for _, tok := range tokenize(text) {
+		if tok == "x" {
+			continue
+		}
+
+		if loose, err := calculate.ParseContent(tok); err == nil {
+			l.loose, l.fit = loose, tok
+			continue
+		}
+
+		if b, err := calculate.ParseBoard(tok); err == nil {
+			l.board = b
+			continue
+		}
+
+		if u, err := calculate.ParseUnit(tok); err == nil {
+			l.unit = u
+			continue
+		}
+
+		num, suffix := splitNumber(tok)
+		if num == "" {
+			return line{}, fmt.Errorf("%w: unknown word %q", ErrSyntax, tok)
+		}
+
+		f, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return line{}, fmt.Errorf("%w: invalid number %q", ErrSyntax, tok)
+		}
+
+		if suffix != "" {
+			u, err := calculate.ParseUnit(suffix)
+			if err != nil {
+				return line{}, fmt.Errorf("%w: %v", ErrSyntax, err)
+			}
+
+			l.unit = u
+		}
+
+		l.dims = append(l.dims, f)
+	}
+
+	return l, nil
+}
+
+// tokenize splits a line into lower case words, separating "5x7" into "5", "x", "7".
+func tokenize(text string) []string {
+	text = strings.ToLower(strings.ReplaceAll(text, "×", " x "))
+
+	var tokens []string
+
+	for _, f := range strings.Fields(text) {
+		parts := strings.Split(f, "x")
+		if len(parts) == 1 || !startsWithNumber(parts[0]) {
+			tokens = append(tokens, f)
+			continue
+		}
+
+		for i, p := range parts {
+			if i > 0 {
+				tokens = append(tokens, "x")
+			}
+
+			if p != "" {
+				tokens = append(tokens, p)
+			}
+		}
+	}
+
+	return tokens
+}
+
+func startsWithNumber(s string) bool {
+	return s != "" && (s[0] == '.' || (s[0] >= '0' && s[0] <= '9'))
+}
+
+// splitNumber splits a token such as "5.5in" into its number and unit suffix.
+func splitNumber(tok string) (string, string) {
+	if !startsWithNumber(tok) {
+		return "", ""
+	}
+
+	i := strings.IndexFunc(tok, func(r rune) bool {
+		return r != '.' && (r < '0' || r > '9')
+	})
+	if i < 0 {
+		return tok, ""
+	}
+
+	return tok[:i], tok[i:]
+}
+
+// Run reads one measurement set per line from r and writes one result line per
+// input line to w, as text or, if the session asks for it, as JSON. Blank lines
+// and lines starting with '#' are ignored, and "quit" or "exit" ends the
+// session. If prompt is not empty it is written before each line is read.
+func (s *Session) Run(r io.Reader, w io.Writer, prompt string) error {
+	scanner := bufio.NewScanner(r)
+
+	for {
+		if prompt != "" {
+			fmt.Fprint(w, prompt)
+		}
+
+		if !scanner.Scan() {
+			break
+		}
+
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case text == "" || strings.HasPrefix(text, "#"):
+			continue
+		case text == "quit" || text == "exit":
+			return nil
+		}
+
+		env, err := s.Eval(text)
+
+		if s.JSON {
+			if err := s.writeJSON(w, env, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		switch {
+		case err != nil:
+			fmt.Fprintf(w, "error: %v\n", err)
+		case env.PaperSize == 0:
+			fmt.Fprintf(w, "board %s, units %s\n", s.Board, s.Unit)
+		default:
+			fmt.Fprintln(w, s.Format(env))
+		}
+	}
+
+	if prompt != "" {
+		fmt.Fprintln(w)
+	}
+
+	return scanner.Err()
+}
+
+// settingsResult is the JSON result of a line that only changes the settings.
+type settingsResult struct {
+	Board calculate.Board `json:"board"`
+	Unit  calculate.Unit  `json:"unit"`
+}
+
+// errorResult is the JSON result of a line that fails.
+type errorResult struct {
+	Error string `json:"error"`
+}
+
+// writeJSON writes the result of a line as a single line of JSON: the
+// envelope, the settings or the error.
+func (s *Session) writeJSON(w io.Writer, env calculate.Envelope, err error) error {
+	var v any = env
+
+	switch {
+	case err != nil:
+		v = errorResult{Error: err.Error()}
+	case env.PaperSize == 0:
+		v = settingsResult{Board: s.Board, Unit: s.Unit}
+	}
+
+	return json.NewEncoder(w).Encode(v)
+}
+
+// Format returns a single line summary of a calculation.
+func (s *Session) Format(env calculate.Envelope) string {
+	return fmt.Sprintf("paper %0.*f %s, punch %0.*f %s",
+		s.Precision, env.PaperSize, env.Spec.Unit, s.Precision, env.PunchLocation, env.Spec.Unit)
+}
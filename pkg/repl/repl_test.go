@@ -0,0 +1,150 @@
+package repl
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func TestSession_Eval(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantSpec  calculate.EnvelopeSpec
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "separated by x",
+			line:      "10 x 8",
+			wantSpec:  calculate.EnvelopeSpec{Length: 10, Width: 8},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "joined by x",
+			line:      "10x8 loose",
+			wantSpec:  calculate.EnvelopeSpec{Length: 10, Width: 8, Loose: true},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "keywords",
+			line:      "5.5 x 4.25 thick mini in",
+			wantSpec:  calculate.EnvelopeSpec{Length: 5.5, Width: 4.25, Loose: true, Board: calculate.BoardMini, Unit: calculate.UnitImperial},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "attached unit",
+			line:      "5.5in × 4.25in",
+			wantSpec:  calculate.EnvelopeSpec{Length: 5.5, Width: 4.25, Unit: calculate.UnitImperial},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "one dimension",
+			line:      "5.5",
+			assertion: assert.Error,
+		},
+		{
+			name:      "unknown word",
+			line:      "5 x 7 purple",
+			assertion: assert.Error,
+		},
+		{
+			name:      "bad unit suffix",
+			line:      "5furlong x 7",
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Session{}
+
+			got, err := s.Eval(tt.line)
+
+			tt.assertion(t, err)
+
+			if err == nil {
+				assert.Equal(t, tt.wantSpec, got.Spec)
+			} else {
+				assert.ErrorIs(t, err, ErrSyntax)
+			}
+		})
+	}
+}
+
+func TestSession_EvalKeepsSettings(t *testing.T) {
+	s := &Session{}
+
+	_, err := s.Eval("mini in")
+	require.NoError(t, err)
+
+	got, err := s.Eval("4 x 3 loose")
+	require.NoError(t, err)
+	assert.Equal(t, calculate.BoardMini, got.Spec.Board)
+	assert.Equal(t, calculate.UnitImperial, got.Spec.Unit)
+	assert.True(t, got.Spec.Loose)
+
+	got, err = s.Eval("4 x 3")
+	require.NoError(t, err)
+	assert.Equal(t, calculate.BoardMini, got.Spec.Board)
+	assert.False(t, got.Spec.Loose)
+}
+
+func TestSession_EvalInvalidKeepsSettings(t *testing.T) {
+	for _, line := range []string{"mini in 5 x 7 purple", "mini 5in", "mini 5furlong x 7", "mini in thick", "snug"} {
+		t.Run(line, func(t *testing.T) {
+			s := &Session{}
+
+			_, err := s.Eval(line)
+			require.ErrorIs(t, err, ErrSyntax)
+
+			assert.Equal(t, calculate.BoardStandard, s.Board)
+			assert.Equal(t, calculate.UnitMetric, s.Unit)
+		})
+	}
+}
+
+func TestSession_RunJSON(t *testing.T) {
+	in := strings.NewReader("10 x 8 loose\nin\n5 x 7 purple\n")
+	out := &bytes.Buffer{}
+
+	s := &Session{Precision: 1, JSON: true}
+	require.NoError(t, s.Run(in, out, ""))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	var env calculate.Envelope
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &env))
+	assert.InDelta(t, 15.7279, env.PaperSize, 1e-4)
+
+	assert.JSONEq(t, `{"board":"standard","unit":"in"}`, lines[1])
+	assert.JSONEq(t, `{"error":"syntax error: unknown word \"purple\""}`, lines[2])
+}
+
+func TestSession_Run(t *testing.T) {
+	in := strings.NewReader("# suite\n10 x 8 loose\n\nin\n0 x 8\nquit\n5 x 7\n")
+	out := &bytes.Buffer{}
+
+	s := &Session{Precision: 1}
+	require.NoError(t, s.Run(in, out, ""))
+
+	assert.Equal(t, []string{
+		"paper 15.7 cm, punch 7.2 cm",
+		"board standard, units in",
+		"error: invalid dimension: length must be greater than zero, got 0",
+	}, strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"))
+}
+
+func TestSession_RunPrompt(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	s := &Session{Precision: 2}
+	require.NoError(t, s.Run(strings.NewReader("10 x 8\n"), out, "> "))
+
+	assert.Equal(t, "> paper 14.93 cm, punch 6.76 cm\n> \n", out.String())
+}
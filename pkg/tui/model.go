@@ -0,0 +1,293 @@
+// Package tui contains the interactive terminal interface for the calculator.
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/diagram"
+)
+
+// SVGFileName is the file written when the layout is exported.
+const SVGFileName = "envelope.svg"
+
+const (
+	leftColumnWidth = 30
+	minDiagramWidth = 16
+	maxDiagramWidth = 64
+)
+
+type field int
+
+const (
+	fieldLength field = iota
+	fieldWidth
+	fieldCount
+)
+
+func (f field) String() string {
+	if f == fieldWidth {
+		return "Width"
+	}
+
+	return "Length"
+}
+
+// Model is the bubbletea model for the calculator. Every change to an input or
+// toggle recalculates the envelope.
+type Model struct {
+	inputs    [fieldCount]string
+	focus     field
+	spec      calculate.EnvelopeSpec
+	precision int
+	env       calculate.Envelope
+	err       error
+	status    string
+	width     int
+
+	copyText  func(string) error
+	writeFile func(string, []byte, os.FileMode) error
+}
+
+// New returns a model with its inputs and toggles initialized from spec. Results
+// are shown with precision decimal places.
+func New(spec calculate.EnvelopeSpec, precision int) Model {
+	m := Model{
+		spec:      spec,
+		precision: precision,
+		copyText:  clipboard.WriteAll,
+		writeFile: os.WriteFile,
+	}
+
+	if spec.Length > 0 {
+		m.inputs[fieldLength] = strconv.FormatFloat(spec.Length, 'f', -1, 64)
+	}
+
+	if spec.Width > 0 {
+		m.inputs[fieldWidth] = strconv.FormatFloat(spec.Width, 'f', -1, 64)
+	}
+
+	m.recalculate()
+
+	return m
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+
+	switch msg.String() {
+	case "ctrl+c", "esc", "q":
+		return m, tea.Quit
+	case "tab", "down", "enter":
+		m.focus = (m.focus + 1) % fieldCount
+	case "shift+tab", "up":
+		m.focus = (m.focus + fieldCount - 1) % fieldCount
+	case "backspace":
+		if in := m.inputs[m.focus]; len(in) > 0 {
+			m.inputs[m.focus] = in[:len(in)-1]
+		}
+	case "b":
+		m.toggleBoard()
+	case "u":
+		m.toggleUnit()
+	case "c":
+		m.spec.Loose = !m.spec.Loose
+	case "y":
+		m.copyResult()
+	case "s":
+		m.exportSVG()
+	default:
+		if msg.Type == tea.KeyRunes {
+			for _, r := range msg.Runes {
+				in := m.inputs[m.focus]
+				if (r >= '0' && r <= '9') || (r == '.' && !strings.ContainsRune(in, '.')) {
+					m.inputs[m.focus] = in + string(r)
+				}
+			}
+		}
+	}
+
+	m.recalculate()
+
+	return m, nil
+}
+
+func (m *Model) toggleBoard() {
+	if m.spec.Board == calculate.BoardMini {
+		m.spec.Board = calculate.BoardStandard
+	} else {
+		m.spec.Board = calculate.BoardMini
+	}
+}
+
+func (m *Model) toggleUnit() {
+	if m.spec.Unit == calculate.UnitImperial {
+		m.spec.Unit = calculate.UnitMetric
+	} else {
+		m.spec.Unit = calculate.UnitImperial
+	}
+}
+
+func (m *Model) recalculate() {
+	for _, f := range []struct {
+		field field
+		value *float64
+	}{{fieldLength, &m.spec.Length}, {fieldWidth, &m.spec.Width}} {
+		// an empty input is zero, which the calculation reports
+		*f.value = 0
+		if in := m.inputs[f.field]; in != "" {
+			v, err := strconv.ParseFloat(in, 64)
+			if err != nil {
+				m.env, m.err = calculate.Envelope{}, fmt.Errorf("%w: %s %q is not a number", calculate.ErrSyntax, f.field, in)
+				return
+			}
+
+			*f.value = v
+		}
+	}
+
+	m.env, m.err = m.spec.Calculate()
+}
+
+func (m *Model) copyResult() {
+	if m.err != nil {
+		m.status = "nothing to copy"
+		return
+	}
+
+	if err := m.copyText(m.Result()); err != nil {
+		m.status = fmt.Sprintf("copy failed: %v", err)
+		return
+	}
+
+	m.status = "copied result to clipboard"
+}
+
+func (m *Model) exportSVG() {
+	if m.err != nil {
+		m.status = "nothing to export"
+		return
+	}
+
+	if err := m.writeFile(SVGFileName, []byte(diagram.SVG(m.env)), 0o600); err != nil {
+		m.status = fmt.Sprintf("export failed: %v", err)
+		return
+	}
+
+	m.status = "saved " + SVGFileName
+}
+
+// Envelope returns the current calculation and any error from the inputs.
+func (m Model) Envelope() (calculate.Envelope, error) {
+	return m.env, m.err
+}
+
+// Result returns the current result as plain text.
+func (m Model) Result() string {
+	if m.err != nil {
+		return m.err.Error()
+	}
+
+	return fmt.Sprintf("Paper size: %0.*f %s\nPunch location: %0.*f %s",
+		m.precision, m.env.PaperSize, m.spec.Unit, m.precision, m.env.PunchLocation, m.spec.Unit)
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	left := []string{"Content (" + m.spec.Unit.String() + ")", ""}
+
+	for f := fieldLength; f < fieldCount; f++ {
+		cursor := " "
+		if f == m.focus {
+			cursor = ">"
+		}
+
+		left = append(left, fmt.Sprintf("%s %-7s %s", cursor, f.String()+":", m.inputs[f]))
+	}
+
+	content := "snug"
+	if m.spec.Loose {
+		content = "loose"
+	}
+
+	left = append(left,
+		"",
+		fmt.Sprintf("  Board:   %-9s (b)", m.spec.Board),
+		fmt.Sprintf("  Units:   %-9s (u)", m.spec.Unit),
+		fmt.Sprintf("  Content: %-9s (c)", content),
+	)
+
+	right := strings.Split(m.Result(), "\n")
+	if m.err == nil {
+		right = append(right, "")
+		right = append(right, strings.Split(strings.TrimSuffix(diagram.ASCII(m.env, m.diagramWidth()), "\n"), "\n")...)
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+
+		if i < len(right) {
+			r = right[i]
+		}
+
+		sb.WriteString(strings.TrimRight(fmt.Sprintf("%-*s%s", leftColumnWidth, l, r), " "))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+
+	if m.status != "" {
+		sb.WriteString(m.status + "\n")
+	}
+
+	sb.WriteString("tab: next field • y: copy result • s: export SVG • q: quit\n")
+
+	return sb.String()
+}
+
+// diagramWidth returns the number of columns available for the layout diagram.
+func (m Model) diagramWidth() int {
+	if m.width == 0 {
+		return diagram.DefaultWidth
+	}
+
+	// leave room for the diagram border
+	w := m.width - leftColumnWidth - 2
+	if w < minDiagramWidth {
+		return minDiagramWidth
+	}
+
+	if w > maxDiagramWidth {
+		return maxDiagramWidth
+	}
+
+	return w
+}
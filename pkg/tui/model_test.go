@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asphaltbuffet/punch-board-calculator/pkg/calculate"
+)
+
+func keys(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func update(t *testing.T, m Model, msgs ...tea.Msg) Model {
+	t.Helper()
+
+	for _, msg := range msgs {
+		got, _ := m.Update(msg)
+
+		var ok bool
+		m, ok = got.(Model)
+		require.True(t, ok)
+	}
+
+	return m
+}
+
+func TestNew(t *testing.T) {
+	m := New(calculate.EnvelopeSpec{Length: 10, Width: 8, Loose: true}, 1)
+
+	env, err := m.Envelope()
+	require.NoError(t, err)
+	assert.InDelta(t, 15.73, env.PaperSize, 0.01)
+	assert.Contains(t, m.View(), "> Length: 10")
+	assert.Contains(t, m.View(), "Paper size: 15.7 cm")
+}
+
+func TestModel_RecalculatesOnKeystroke(t *testing.T) {
+	m := New(calculate.EnvelopeSpec{}, 1)
+
+	_, err := m.Envelope()
+	assert.ErrorIs(t, err, calculate.ErrInvalidDimension)
+
+	m = update(t, m, keys("1"), keys("0"), tea.KeyMsg{Type: tea.KeyTab}, keys("8"))
+	env, err := m.Envelope()
+	require.NoError(t, err)
+	assert.InDelta(t, 14.93, env.PaperSize, 0.01)
+
+	m = update(t, m, keys("c"))
+	env, err = m.Envelope()
+	require.NoError(t, err)
+	assert.InDelta(t, 15.73, env.PaperSize, 0.01)
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+	_, err = m.Envelope()
+	assert.Error(t, err)
+}
+
+func TestModel_Toggles(t *testing.T) {
+	m := New(calculate.EnvelopeSpec{Length: 4, Width: 3}, 1)
+
+	m = update(t, m, keys("b"), keys("u"))
+	env, err := m.Envelope()
+	require.NoError(t, err)
+	assert.Equal(t, calculate.BoardMini, env.Spec.Board)
+	assert.Equal(t, calculate.UnitImperial, env.Spec.Unit)
+	assert.Contains(t, m.View(), "Board:   mini")
+
+	m = update(t, m, keys("b"), keys("u"))
+	env, err = m.Envelope()
+	require.NoError(t, err)
+	assert.Equal(t, calculate.BoardStandard, env.Spec.Board)
+	assert.Equal(t, calculate.UnitMetric, env.Spec.Unit)
+}
+
+func TestModel_IgnoresNonNumericInput(t *testing.T) {
+	m := New(calculate.EnvelopeSpec{}, 1)
+
+	m = update(t, m, keys("1x.5"))
+	assert.Equal(t, "1.5", m.inputs[fieldLength])
+
+	// a second decimal point is ignored
+	m = update(t, m, keys(".3"))
+	assert.Equal(t, "1.53", m.inputs[fieldLength])
+}
+
+func TestModel_ReportsUnparsableInput(t *testing.T) {
+	m := New(calculate.EnvelopeSpec{Width: 8}, 1)
+
+	m = update(t, m, keys("."))
+	_, err := m.Envelope()
+	assert.ErrorIs(t, err, calculate.ErrSyntax)
+	assert.EqualError(t, err, `invalid syntax: Length "." is not a number`)
+
+	m = update(t, m, keys("5"))
+	_, err = m.Envelope()
+	assert.NoError(t, err)
+}
+
+func TestModel_CopyResult(t *testing.T) {
+	var copied string
+
+	m := New(calculate.EnvelopeSpec{Length: 10, Width: 8, Loose: true}, 1)
+	m.copyText = func(s string) error {
+		copied = s
+		return nil
+	}
+
+	m = update(t, m, keys("y"))
+	assert.Equal(t, "Paper size: 15.7 cm\nPunch location: 7.2 cm", copied)
+	assert.Contains(t, m.View(), "copied result to clipboard")
+
+	m.copyText = func(string) error { return errors.New("no clipboard") }
+	m = update(t, m, keys("y"))
+	assert.Contains(t, m.View(), "copy failed: no clipboard")
+}
+
+func TestModel_ExportSVG(t *testing.T) {
+	var (
+		gotName string
+		gotData []byte
+	)
+
+	m := New(calculate.EnvelopeSpec{Length: 10, Width: 8}, 1)
+	m.writeFile = func(name string, data []byte, _ os.FileMode) error {
+		gotName, gotData = name, data
+		return nil
+	}
+
+	m = update(t, m, keys("s"))
+	assert.Equal(t, SVGFileName, gotName)
+	assert.Contains(t, string(gotData), "<svg ")
+	assert.Contains(t, m.View(), "saved "+SVGFileName)
+}
+
+func TestModel_Quit(t *testing.T) {
+	m := New(calculate.EnvelopeSpec{}, 1)
+
+	_, cmd := m.Update(keys("q"))
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+}